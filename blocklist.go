@@ -0,0 +1,228 @@
+package gokord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/event"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type BlockType string
+
+const (
+	BlockUser  BlockType = "user"
+	BlockGuild BlockType = "guild"
+
+	// DefaultBlocklistCacheTTL is the duration of the redis cache of the Blocklist
+	DefaultBlocklistCacheTTL = 10 * time.Minute
+)
+
+// BlockedMessages are sent when a blocked user (or a user in a blocked guild) uses the Bot, indexed by the locale of
+// the user.
+//
+// The message linked with "" is used if the locale is not present.
+var BlockedMessages = map[string]string{
+	"":   "You are not allowed to use this bot.",
+	"fr": "Tu n'as pas le droit d'utiliser ce bot.",
+}
+
+var ErrInvalidBlockType = errors.New("invalid block type")
+
+// BlockEntry is a user or a guild in the Blocklist
+type BlockEntry struct {
+	Type      BlockType `gorm:"primaryKey"`
+	TargetID  string    `gorm:"primaryKey"`
+	Reason    string
+	AddedBy   string
+	CreatedAt time.Time
+	ExpiresAt *time.Time `gorm:"index"` // ExpiresAt is nil if the BlockEntry never expires
+}
+
+// IsExpired returns true if the BlockEntry is expired
+func (e *BlockEntry) IsExpired() bool {
+	return e.ExpiresAt != nil && !e.ExpiresAt.After(time.Now())
+}
+
+// Blocklist contains users and guilds not allowed to use the Bot.
+// It is stored in the database and can be cached in redis.
+type Blocklist struct {
+	db    *gorm.DB
+	cache *redis.Client
+	// CacheTTL is the duration of the redis cache (DefaultBlocklistCacheTTL by default)
+	CacheTTL time.Duration
+}
+
+// NewBlocklist creates a Blocklist using the given database.
+// cache may be nil to disable the redis cache.
+// It migrates BlockEntry.
+func NewBlocklist(db *gorm.DB, cache *redis.Client) (*Blocklist, error) {
+	if err := db.AutoMigrate(&BlockEntry{}); err != nil {
+		return nil, err
+	}
+	return &Blocklist{db: db, cache: cache, CacheTTL: DefaultBlocklistCacheTTL}, nil
+}
+
+// Add the target to the Blocklist (replacing the previous BlockEntry).
+// If duration is 0, the BlockEntry never expires.
+func (bl *Blocklist) Add(ctx context.Context, t BlockType, id string, reason string, duration time.Duration, by string) (*BlockEntry, error) {
+	if t != BlockUser && t != BlockGuild {
+		return nil, ErrInvalidBlockType
+	}
+	e := &BlockEntry{Type: t, TargetID: id, Reason: reason, AddedBy: by, CreatedAt: time.Now()}
+	if duration > 0 {
+		exp := e.CreatedAt.Add(duration)
+		e.ExpiresAt = &exp
+	}
+	if err := bl.db.WithContext(ctx).Save(e).Error; err != nil {
+		return nil, err
+	}
+	return e, bl.invalidate(ctx, t, id)
+}
+
+// Remove the target from the Blocklist.
+// It returns false if the target was not in the Blocklist.
+func (bl *Blocklist) Remove(ctx context.Context, t BlockType, id string) (bool, error) {
+	res := bl.db.WithContext(ctx).Where("type = ? AND target_id = ?", t, id).Delete(&BlockEntry{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, bl.invalidate(ctx, t, id)
+}
+
+// List every BlockEntry not expired
+func (bl *Blocklist) List(ctx context.Context) ([]*BlockEntry, error) {
+	var entries []*BlockEntry
+	err := bl.db.WithContext(ctx).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at").
+		Find(&entries).Error
+	return entries, err
+}
+
+// Get the BlockEntry of the target.
+// It returns nil if the target is not blocked.
+func (bl *Blocklist) Get(ctx context.Context, t BlockType, id string) (*BlockEntry, error) {
+	if id == "" {
+		return nil, nil
+	}
+	if bl.cache != nil {
+		b, err := bl.cache.Get(ctx, blocklistKey(t, id)).Bytes()
+		if err == nil {
+			if len(b) == 0 {
+				return nil, nil
+			}
+			var e BlockEntry
+			if err = json.Unmarshal(b, &e); err == nil && !e.IsExpired() {
+				return &e, nil
+			}
+		} else if !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+	}
+	var e BlockEntry
+	err := bl.db.WithContext(ctx).Where("type = ? AND target_id = ?", t, id).First(&e).Error
+	found := true
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && e.IsExpired()) {
+		found = false
+	} else if err != nil {
+		return nil, err
+	}
+	if bl.cache != nil {
+		ttl := bl.CacheTTL
+		var b []byte
+		if found {
+			if e.ExpiresAt != nil {
+				ttl = min(ttl, time.Until(*e.ExpiresAt))
+			}
+			if b, err = json.Marshal(&e); err != nil {
+				return nil, err
+			}
+		}
+		if err = bl.cache.Set(ctx, blocklistKey(t, id), b, ttl).Err(); err != nil {
+			return nil, err
+		}
+	}
+	if !found {
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// IsBlocked returns the BlockEntry of the user or of the guild of the interaction.
+// It returns nil if neither is blocked.
+func (bl *Blocklist) IsBlocked(ctx context.Context, i *event.InteractionCreate) (*BlockEntry, error) {
+	e, err := bl.Get(ctx, BlockUser, cmd.InteractionUserID(i))
+	if err != nil || e != nil {
+		return e, err
+	}
+	return bl.Get(ctx, BlockGuild, i.GuildID)
+}
+
+func (bl *Blocklist) invalidate(ctx context.Context, t BlockType, id string) error {
+	if bl.cache == nil {
+		return nil
+	}
+	return bl.cache.Del(ctx, blocklistKey(t, id)).Err()
+}
+
+func blocklistKey(t BlockType, id string) string {
+	return fmt.Sprintf("gokord:blocklist:%s:%s", t, id)
+}
+
+// isBlocked returns true if the interaction must be ignored because of the Blocklist.
+// It sends the denial message with resp and leaves the guild if Bot.LeaveBlockedGuilds is true.
+func (b *Bot) isBlocked(s bot.Session, i *event.InteractionCreate, resp *cmd.ResponseBuilder) bool {
+	if b.Blocklist == nil || b.IsOwner(cmd.InteractionUserID(i)) {
+		return false
+	}
+	e, err := b.Blocklist.IsBlocked(resp.Context(), i)
+	if err != nil {
+		// a broken blocklist must not break the bot
+		b.Logger.Error("checking blocklist", "error", err)
+		return false
+	}
+	if e == nil {
+		return false
+	}
+	b.Logger.Debug("interaction blocked", "type", e.Type, "target", e.TargetID)
+	err = resp.IsEphemeral().SetMessage(cmd.LocalizedMessage(BlockedMessages, string(i.Locale))).Send()
+	if err != nil {
+		b.Logger.Error("sending blocked message", "error", err)
+	}
+	if e.Type == BlockGuild {
+		b.leaveBlockedGuild(s, e.TargetID)
+	}
+	return true
+}
+
+// onGuildCreateBlocklist leaves the guild if it is blocked and if Bot.LeaveBlockedGuilds is true
+func (b *Bot) onGuildCreateBlocklist(ctx context.Context, s bot.Session, g *event.GuildCreate) {
+	if b.Blocklist == nil || !b.LeaveBlockedGuilds {
+		return
+	}
+	e, err := b.Blocklist.Get(ctx, BlockGuild, g.ID)
+	if err != nil {
+		b.Logger.Error("checking blocklist", "error", err, "guild", g.ID)
+		return
+	}
+	if e != nil {
+		b.leaveBlockedGuild(s, g.ID)
+	}
+}
+
+func (b *Bot) leaveBlockedGuild(s bot.Session, guildID string) {
+	if !b.LeaveBlockedGuilds {
+		return
+	}
+	b.Logger.Info("leaving blocked guild", "guild", guildID)
+	if err := s.GuildAPI().Leave(guildID); err != nil {
+		b.Logger.Error("leaving blocked guild", "error", err, "guild", guildID)
+	}
+}
@@ -0,0 +1,136 @@
+package gokord
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/channel"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+)
+
+// blocklistEntriesPerPage is the number of BlockEntry displayed per page of /blocklist list
+const blocklistEntriesPerPage = 10
+
+// blocklistCommand returns the owner-only command managing the Blocklist
+func (b *Bot) blocklistCommand() cmd.CommandBuilder {
+	typeOpt := func() cmd.CommandOptionBuilder {
+		return cmd.NewOption(types.CommandOptionString, "type", "Type of the target").IsRequired().
+			AddChoice(cmd.NewChoice("User", string(BlockUser))).
+			AddChoice(cmd.NewChoice("Guild", string(BlockGuild)))
+	}
+	idOpt := func() cmd.CommandOptionBuilder {
+		return cmd.NewOption(types.CommandOptionString, "id", "ID of the target").IsRequired()
+	}
+	return cmd.New("blocklist", "Manage the blocklist of the bot").
+		AddGuard(cmd.OwnerOnly()).
+		AddContext(types.InteractionContextGuild).
+		AddContext(types.InteractionContextBotDM).
+		AddSub(cmd.New("add", "Block a user or a guild").
+			AddOption(typeOpt()).
+			AddOption(idOpt()).
+			AddOption(cmd.NewOption(types.CommandOptionString, "reason", "Reason of the block")).
+			AddOption(cmd.NewOption(types.CommandOptionInteger, "days", "Duration of the block (forever if not set)")).
+			SetHandler(b.blocklistAdd)).
+		AddSub(cmd.New("remove", "Unblock a user or a guild").
+			AddOption(typeOpt()).
+			AddOption(idOpt()).
+			SetHandler(b.blocklistRemove)).
+		AddSub(cmd.New("list", "List blocked users and guilds").
+			SetHandler(b.blocklistList))
+}
+
+func (b *Bot) blocklistAdd(s bot.Session, i *event.InteractionCreate, optMap cmd.OptionMap, resp *cmd.ResponseBuilder) {
+	resp.IsEphemeral()
+	t := BlockType(optMap["type"].StringValue())
+	id := strings.TrimSpace(optMap["id"].StringValue())
+	var reason string
+	if opt, ok := optMap["reason"]; ok {
+		reason = opt.StringValue()
+	}
+	var duration time.Duration
+	if opt, ok := optMap["days"]; ok && opt.IntValue() > 0 {
+		duration = time.Duration(opt.IntValue()) * 24 * time.Hour
+	}
+	e, err := b.Blocklist.Add(resp.Context(), t, id, reason, duration, cmd.InteractionUserID(i))
+	if err != nil {
+		b.Logger.Error("adding to blocklist", "error", err, "type", t, "target", id)
+		if err = resp.SetMessage("Impossible to add the entry to the blocklist.").Send(); err != nil {
+			b.Logger.Error("sending error", "error", err)
+		}
+		return
+	}
+	if t == BlockGuild {
+		if _, err = s.GuildAPI().State.Guild(id); err == nil {
+			b.leaveBlockedGuild(s, id)
+		}
+	}
+	if err = resp.SetMessage("Blocked: " + formatBlockEntry(e)).Send(); err != nil {
+		b.Logger.Error("sending blocklist response", "error", err)
+	}
+}
+
+func (b *Bot) blocklistRemove(_ bot.Session, _ *event.InteractionCreate, optMap cmd.OptionMap, resp *cmd.ResponseBuilder) {
+	resp.IsEphemeral()
+	t := BlockType(optMap["type"].StringValue())
+	id := strings.TrimSpace(optMap["id"].StringValue())
+	ok, err := b.Blocklist.Remove(resp.Context(), t, id)
+	var msg string
+	if err != nil {
+		b.Logger.Error("removing from blocklist", "error", err, "type", t, "target", id)
+		msg = "Impossible to remove the entry from the blocklist."
+	} else if !ok {
+		msg = fmt.Sprintf("The %s `%s` is not blocked.", t, id)
+	} else {
+		msg = fmt.Sprintf("The %s `%s` is not blocked anymore.", t, id)
+	}
+	if err = resp.SetMessage(msg).Send(); err != nil {
+		b.Logger.Error("sending blocklist response", "error", err)
+	}
+}
+
+func (b *Bot) blocklistList(_ bot.Session, _ *event.InteractionCreate, _ cmd.OptionMap, resp *cmd.ResponseBuilder) {
+	resp.IsEphemeral()
+	entries, err := b.Blocklist.List(resp.Context())
+	if err != nil {
+		b.Logger.Error("listing blocklist", "error", err)
+		if err = resp.SetMessage("Impossible to list the blocklist.").Send(); err != nil {
+			b.Logger.Error("sending error", "error", err)
+		}
+		return
+	}
+	if len(entries) == 0 {
+		if err = resp.SetMessage("The blocklist is empty.").Send(); err != nil {
+			b.Logger.Error("sending blocklist response", "error", err)
+		}
+		return
+	}
+	var pages []*channel.MessageEmbed
+	for start := 0; start < len(entries); start += blocklistEntriesPerPage {
+		var sb strings.Builder
+		for _, e := range entries[start:min(start+blocklistEntriesPerPage, len(entries))] {
+			sb.WriteString("- " + formatBlockEntry(e) + "\n")
+		}
+		pages = append(pages, &channel.MessageEmbed{
+			Title:       fmt.Sprintf("Blocklist (%d)", len(entries)),
+			Description: sb.String(),
+		})
+	}
+	if err = cmd.NewPaginator(cmd.StaticPages(pages...)).Send(resp); err != nil {
+		b.Logger.Error("sending blocklist", "error", err)
+	}
+}
+
+func formatBlockEntry(e *BlockEntry) string {
+	s := fmt.Sprintf("%s `%s`", e.Type, e.TargetID)
+	if e.Reason != "" {
+		s += " — " + e.Reason
+	}
+	if e.ExpiresAt != nil {
+		s += fmt.Sprintf(" (until <t:%d:f>)", e.ExpiresAt.Unix())
+	}
+	return s
+}
@@ -2,12 +2,14 @@ package gokord
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"math/rand/v2"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,18 +17,25 @@ import (
 	discordgo "github.com/nyttikord/gokord"
 	"github.com/nyttikord/gokord/bot"
 	"github.com/nyttikord/gokord/discord"
-	"github.com/nyttikord/gokord/discord/types"
 	"github.com/nyttikord/gokord/event"
 	"github.com/nyttikord/gokord/interaction"
+	"github.com/nyttikord/gokord/user"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 )
 
 var (
+	// Debug is true if the bot is in debug mode
+	//
+	// Deprecated: use Bot.Debug (set by Bot.SetupConfigs).
 	Debug = true
 
 	ErrBadStatusType     = errors.New("bad status type, please use the constant")
 	ErrStatusUrlNotFound = errors.New("status url not found")
-
-	registeredCommands []*interaction.Command
+	ErrBotAlreadyOpen    = errors.New("bot is already open")
+	ErrBotNotOpen        = errors.New("bot is not open")
+	ErrOpeningBot        = errors.New("error while opening bot")
+	ErrClosingBot        = errors.New("error while closing bot")
 )
 
 type StatusType int
@@ -42,18 +51,101 @@ const (
 
 // Bot is the representation of a discord bot
 type Bot struct {
-	Logger      *slog.Logger
-	Token       string                     // Token of the Bot
-	Status      []*Status                  // Status of the Bot
-	Commands    []cmd.CommandBuilder       // Commands of the Bot, use New to create easily a new command
-	handlers    []any                      // handlers of the Bot
-	AfterInit   func(s *discordgo.Session) // AfterInit is called after the initialization process of the Bot
+	Logger   *slog.Logger
+	Token    string               // Token of the Bot
+	Status   []*Status            // Status of the Bot
+	Commands []cmd.CommandBuilder // Commands of the Bot, use New to create easily a new command
+	handlers []any                // handlers of the Bot
+	// eventMiddlewares applied to EventHandler
+	eventMiddlewares []EventMiddleware
+	// commandMiddlewares applied to the handlers of commands
+	commandMiddlewares []CommandMiddleware
+	// AfterInit is called after the initialization process of the Bot.
+	//
+	// Deprecated: use OnFirstReady or OnBeforeConnect.
+	AfterInit   func(s *discordgo.Session)
 	Version     *Version
 	Innovations []*Innovation
 	Name        string
 	Intents     discord.Intent
-	timerCancel chan<- any
 	Verbose     bool
+	// statusTimers updating the status of each session
+	statusTimers   map[bot.Session]chan<- any
+	statusTimersMu sync.Mutex
+	// Config of the Bot, set by SetupConfigs
+	Config BaseConfig
+	// ConfigName is the name of the file of Config ("config" if empty)
+	ConfigName string
+	// DB used by the Bot, set by SetupConfigs
+	DB *gorm.DB
+	// ownedDB is the DB opened by SetupConfigs, closed with the Bot
+	ownedDB *gorm.DB
+	// Debug is true if the Bot is in debug mode (commands are only registered in the development guilds), set by
+	// SetupConfigs
+	Debug bool
+	// legacy is true if the Bot uses the deprecated global variables (SetupConfigs was called instead of
+	// Bot.SetupConfigs)
+	legacy             bool
+	cmdMap             map[string]cmd.CommandHandler
+	registeredCommands []*interaction.Command
+	redisClients       []*redis.Client
+	redisClientsMu     sync.Mutex
+	Branding           *cmd.Branding // Branding applied to embeds, cmd.DefaultBranding if nil
+	// CooldownStore used by commands with a cmd.Cooldown.
+	// If nil, redis is used if the Config contains RedisCredentials, else the cooldowns are stored in memory
+	CooldownStore CooldownStore
+	// Owners of the Bot (IDs) in addition to the owner of the application (or the members of its team) fetched at
+	// startup
+	Owners []string
+	// Blocklist checked before every handler (disabled if nil).
+	// The owner-only command /blocklist is registered if it is not nil
+	Blocklist *Blocklist
+	// LeaveBlockedGuilds makes the Bot leave guilds in the Blocklist
+	LeaveBlockedGuilds bool
+	// Maintenance applied on start (overridden by the config if Config implements MaintenanceConfig)
+	Maintenance *Maintenance
+	// MaintenanceCommand registers the owner-only command /maintenance
+	MaintenanceCommand bool
+	maintenance        *Maintenance
+	maintenanceMu      sync.RWMutex
+	maintenanceShared  bool
+	maintenanceRedis   *redis.Client
+	maintenanceCancel  chan<- any
+	// ModulesCommand registers the command /modules, used by administrators to enable and disable toggleable modules
+	// in their guild
+	ModulesCommand bool
+	// Sharding of the connection to the gateway (a single session is used if nil)
+	Sharding *Sharding
+	shards   shards
+	shardsMu sync.RWMutex
+	// PrefixCommands allows using commands with a prefix in messages (disabled if nil)
+	PrefixCommands *PrefixCommands
+	prefixes       prefixes
+	modules        modules
+	services       *cmd.Services
+	servicesOnce   sync.Once
+	// DrainTimeout is the maximum duration to wait for running handlers when the Bot is closed
+	// (DefaultDrainTimeout if not set)
+	DrainTimeout  time.Duration
+	shutdownHooks []ShutdownHook
+	inflight      inflight
+	hooks         hooks
+	// lifetime is cancelled when the Bot is closed
+	lifetime       context.Context
+	cancelLifetime context.CancelFunc
+	session        *discordgo.Session
+	sessionMu      sync.Mutex
+	// appID is the ID of the application, fetched for HTTP interactions
+	appID string
+	// http is true if the Bot receives interactions over HTTP (see OpenHTTP)
+	http bool
+	// user of the Bot, fetched for HTTP interactions
+	user         *user.User
+	setupOnce    sync.Once
+	owners       []string
+	ownersMu     sync.RWMutex
+	router       *cmd.Router
+	cooldownOnce sync.Once
 }
 
 // Status contains all required information for updating the status
@@ -63,89 +155,366 @@ type Status struct {
 	Url     string     // Url of the StreamingStatus
 }
 
-// Start the Bot (blocking instruction)
+// Start the Bot (blocking instruction) until SIGINT or SIGTERM is received.
+//
+// Deprecated: use Run with NotifyContext to handle errors.
 func (b *Bot) Start(ctx context.Context) {
+	ctx, stop := NotifyContext(ctx)
+	defer stop()
+	if err := b.Run(ctx); err != nil {
+		b.logger().Error("running bot", "error", err)
+	}
+}
+
+// NotifyContext returns a copy of ctx cancelled when SIGINT or SIGTERM is received.
+// It is designed to be used with Run.
+func NotifyContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
+}
+
+// Run the Bot until ctx is cancelled (blocking instruction).
+// The Bot is closed with a timeout of Bot.DrainTimeout plus 5 seconds.
+func (b *Bot) Run(ctx context.Context) error {
+	if err := b.Open(ctx); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	closeCtx, cancel := context.WithTimeout(context.Background(), b.drainTimeout()+5*time.Second)
+	defer cancel()
+	return b.Close(closeCtx)
+}
+
+// Open the connection to Discord and set up the Bot (non-blocking instruction).
+// If Bot.Sharding is set, a session is opened for each shard run by the process.
+// Use Close to stop the Bot.
+func (b *Bot) Open(ctx context.Context) error {
+	b.sessionMu.Lock()
+	defer b.sessionMu.Unlock()
+	if b.session != nil {
+		return ErrBotAlreadyOpen
+	}
+	dg, err := b.newSession(ctx)
+	if err != nil {
+		return err
+	}
+	b.http = false
+	b.appID = ""
+	b.user = nil
+
+	if err = b.setupShards(ctx, dg); err != nil {
+		b.stopTimers()
+		b.cancelLifetime()
+		return errors.Join(ErrOpeningBot, err)
+	}
+	err = b.openSession(ctx, dg) // Starts the bot
+	if err == nil {
+		err = runHooks(ctx, dg, b.hooks.firstReady)
+		if err != nil {
+			dg.ForceClose()
+		}
+	}
+	if err == nil {
+		err = b.openShards(ctx)
+	}
+	if err != nil {
+		b.stopTimers()
+		b.cancelLifetime()
+		b.releaseShards(ctx)
+		return errors.Join(ErrOpeningBot, err)
+	}
+	b.start(dg)
+	b.setupCommandsHandlers(dg)
+
+	if b.AfterInit != nil {
+		b.AfterInit(dg)
+	}
+	return nil
+}
+
+// newSession creates the session of the Bot and sets up everything needed before connecting to Discord
+func (b *Bot) newSession(ctx context.Context) (_ *discordgo.Session, err error) {
+	b.useGlobals()
+	b.lifetime, b.cancelLifetime = context.WithCancel(context.WithoutCancel(ctx))
+	defer func() {
+		if err != nil {
+			b.stopTimers()
+			b.cancelLifetime()
+		}
+	}()
+	dg := b.createSession()
+	b.Logger = dg.Logger()
+
+	b.inflight.mu.Lock()
+	// handlers abandoned by the previous Close are forgotten
+	b.inflight.draining = false
+	b.inflight.running = nil
+	b.inflight.idle = nil
+	b.inflight.mu.Unlock()
+
+	if err = b.setupModules(); err != nil {
+		return nil, errors.Join(ErrOpeningBot, err)
+	}
+	if err = b.setupPrefixCommands(); err != nil {
+		return nil, errors.Join(ErrOpeningBot, err)
+	}
+
+	b.setupOnce.Do(func() {
+		b.Commands = append(b.Commands, pingCommandBuilder())
+		if b.Blocklist != nil {
+			b.Commands = append(b.Commands, b.blocklistCommand())
+		}
+		if b.MaintenanceCommand {
+			b.Commands = append(b.Commands, b.maintenanceCommand())
+		}
+		if b.ModulesCommand {
+			b.Commands = append(b.Commands, b.modulesCommand())
+		}
+	})
+
+	b.addHandlers(dg)
+	b.setupMaintenance()
+
+	if err = runHooks(ctx, dg, b.hooks.beforeConnect); err != nil {
+		return nil, err
+	}
+	return dg, nil
+}
+
+// createSession creates a new session connecting to Discord with the token of the Bot
+func (b *Bot) createSession() *discordgo.Session {
 	level := slog.LevelInfo
-	if Debug || b.Verbose {
+	if b.Debug || b.Verbose {
 		level = slog.LevelDebug
 	}
 	dg := discordgo.NewWithLogLevel("Bot "+b.Token, level) // New connection to the discord API with bot token
-
 	dg.Identify.Intents = b.Intents
-	b.Logger = dg.Logger()
+	return dg
+}
 
+// addHandlers registers the event handlers of the Bot in the session
+func (b *Bot) addHandlers(dg *discordgo.Session) {
 	dg.EventManager().AddHandler(b.onReady)
-
+	dg.EventManager().AddHandler(b.onReadyHooks)
+	dg.EventManager().AddHandler(b.onGuildCreateHooks)
+	dg.EventManager().AddHandler(b.onGuildDeleteHooks)
+	if b.Blocklist != nil {
+		dg.EventManager().AddHandler(b.onGuildCreateBlocklist)
+	}
 	for _, handler := range b.handlers {
-		dg.EventManager().AddHandler(handler)
+		dg.EventManager().AddHandler(b.withServices(handler))
 	}
+}
 
-	err := dg.Open(ctx) // Starts the bot
-	if err != nil {
-		b.Logger.Error("starting bot", "error", err)
-		return
+// openSession opens the session and waits for its first Ready
+func (b *Bot) openSession(ctx context.Context, dg *discordgo.Session) error {
+	if dg.Identify.Shard != nil {
+		if err := b.waitIdentify(ctx, dg.Identify.Shard[0]); err != nil {
+			return err
+		}
+	}
+	ready := make(chan struct{}, 1)
+	removeReady := dg.EventManager().AddHandler(func(context.Context, bot.Session, *event.Ready) {
+		select {
+		case ready <- struct{}{}:
+		default:
+		}
+	})
+	defer removeReady()
+
+	if err := dg.Open(ctx); err != nil {
+		return err
 	}
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		dg.ForceClose()
+		return ctx.Err()
+	}
+}
+
+// start the Bot once it is connected: it registers the commands in background if the process runs the primary shard
+func (b *Bot) start(dg *discordgo.Session) {
+	b.session = dg
+	b.fetchOwners(dg)
 
+	if !b.PrimaryShard() {
+		return
+	}
 	// register commands
 	go func() {
 		st := time.Now()
 		b.updateCommands(dg)
 		b.Logger.Info("commands updated", "in", time.Since(st))
+		b.logHooks(b.lifetime, dg, "commands synced", b.hooks.commandsSynced)
 	}()
-	b.setupCommandsHandlers(dg)
+}
 
-	if Debug {
-		dg.EventManager().AddHandler(func(_ context.Context, s bot.Session, i *event.InteractionCreate) {
-			b.Logger.Debug("interaction received")
-			data, _ := json.Marshal(i)
-			b.Logger.Debug(string(data))
-		})
-	}
-	if b.AfterInit != nil {
-		b.AfterInit(dg)
+// Close the Bot.
+//
+// It stops accepting new interactions and waits for the running handlers (up to Bot.DrainTimeout or until ctx is
+// done), closes the connection to Discord (forced if ctx is done before), and then calls every ShutdownHook added
+// with OnShutdown before closing DB and redis clients opened by gokord.
+//
+// It returns ErrHandlersAbandoned if handlers were still running.
+// SetupConfigs must be called again before opening the Bot again, because DB is closed.
+func (b *Bot) Close(ctx context.Context) error {
+	b.sessionMu.Lock()
+	defer b.sessionMu.Unlock()
+	dg := b.session
+	if dg == nil {
+		return ErrBotNotOpen
 	}
-
-	sc := make(chan os.Signal, 1)
-	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
-	<-sc
+	b.session = nil
 
 	b.Logger.Info("stopping bot")
+	b.logHooks(ctx, dg, "before shutdown", b.hooks.beforeShutdown)
+	b.cancelLifetime()
 
-	if b.timerCancel != nil {
-		b.timerCancel <- struct{}{}
+	drainCtx, cancel := context.WithTimeout(ctx, b.drainTimeout())
+	abandoned := b.drain(drainCtx)
+	cancel()
+	var errs []error
+	if len(abandoned) > 0 {
+		b.Logger.Warn("handlers abandoned", "count", len(abandoned), "handlers", abandoned)
+		errs = append(errs, fmt.Errorf("%w: %s", ErrHandlersAbandoned, strings.Join(abandoned, ", ")))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	if b.Debug && b.PrimaryShard() {
+		b.cleanDevGuilds(dg)
+	}
+	b.stopTimers()
 
-	closed := make(chan struct{}, 1)
-	go func() {
-		if err = dg.Close(ctx); err != nil {
-			dg.Logger().Error("closing bot", "error", err)
-			dg.Logger().Warn("force closing")
-			dg.ForceClose()
+	var err error
+	if b.http {
+		// opened with OpenHTTP: there is no gateway connection
+		b.http = false
+		b.appID = ""
+		b.user = nil
+	} else {
+		sessions := b.sessions()
+		closed := make(chan error, 1)
+		go func() {
+			var closeErrs []error
+			for _, s := range sessions {
+				closeErrs = append(closeErrs, s.Close(ctx))
+			}
+			closed <- errors.Join(closeErrs...)
+		}()
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case err = <-closed:
 		}
-		closed <- struct{}{}
-	}()
+		if err != nil {
+			b.Logger.Error("closing bot", "error", err)
+			b.Logger.Warn("force closing")
+			for _, s := range sessions {
+				s.ForceClose()
+			}
+			errs = append(errs, errors.Join(ErrClosingBot, err))
+		}
+	}
+	b.releaseShards(ctx)
+	b.hooks.guildsMu.Lock()
+	b.hooks.guilds = nil
+	b.hooks.guildsMu.Unlock()
 
-	select {
-	case <-ctx.Done():
-		dg.Logger().Error("closing bot", "error", ctx.Err())
-		dg.ForceClose()
-	case <-closed:
-		// do nothing
+	if err = b.runShutdownHooks(ctx); err != nil {
+		b.Logger.Error("running shutdown hooks", "error", err)
+		errs = append(errs, err)
 	}
 
 	b.Logger.Info("bot shut down")
+	return errors.Join(errs...)
+}
+
+// drainTimeout returns Bot.DrainTimeout, or DefaultDrainTimeout if it is not set
+func (b *Bot) drainTimeout() time.Duration {
+	if b.DrainTimeout <= 0 {
+		return DefaultDrainTimeout
+	}
+	return b.DrainTimeout
+}
+
+// useGlobals uses the deprecated global variables if Bot.SetupConfigs was not called
+func (b *Bot) useGlobals() {
+	if b.Config != nil {
+		return
+	}
+	b.legacy = true
+	b.Config = BaseCfg
+	if b.DB == nil {
+		b.DB = DB
+	}
+	b.Debug = Debug
+}
+
+// stopTimers of the Bot
+func (b *Bot) stopTimers() {
+	b.statusTimersMu.Lock()
+	for _, t := range b.statusTimers {
+		StopTimer(t)
+	}
+	b.statusTimers = nil
+	b.statusTimersMu.Unlock()
+	b.shardsMu.Lock()
+	StopTimer(b.shards.renewCancel)
+	b.shards.renewCancel = nil
+	b.shardsMu.Unlock()
+	StopTimer(b.maintenanceCancel)
+	b.maintenanceCancel = nil
+}
+
+// context returns the context of the Bot, cancelled when it is closed (context.Background if it was never opened)
+func (b *Bot) context() context.Context {
+	if b.lifetime == nil {
+		return context.Background()
+	}
+	return b.lifetime
+}
+
+// logger returns Bot.Logger, or slog.Default if the Bot was never opened
+func (b *Bot) logger() *slog.Logger {
+	if b.Logger == nil {
+		return slog.Default()
+	}
+	return b.Logger
 }
 
-func (b *Bot) onReady(ctx context.Context, s bot.Session, _ *event.Ready) {
+func (b *Bot) onReady(_ context.Context, s bot.Session, _ *event.Ready) {
 	b.Logger.Info("bot started", "as", s.SessionState().User().Username)
-	b.timerCancel = NewTimer(30*time.Second, func(stop chan<- any) {
+	// Ready is received again after a reconnection
+	b.statusTimersMu.Lock()
+	defer b.statusTimersMu.Unlock()
+	if b.statusTimers == nil {
+		b.statusTimers = make(map[bot.Session]chan<- any)
+	}
+	StopTimer(b.statusTimers[s])
+	// the status is set for each shard
+	ctx := b.lifetime
+	maintenance := false
+	b.statusTimers[s] = NewTimer(30*time.Second, func(chan<- any) {
+		if m := b.GetMaintenance(); m != nil && m.Status != "" {
+			if err := s.BotAPI().UpdateCustomStatus(ctx, m.Status); err != nil {
+				b.Logger.Error("updating maintenance status", "error", err)
+			}
+			maintenance = true
+			return
+		}
 		if b.Status == nil {
-			stop <- struct{}{}
+			// not disabled to display the status of the maintenance
+			if maintenance {
+				// removes the status of the ended maintenance
+				if err := s.BotAPI().UpdateCustomStatus(ctx, ""); err != nil {
+					b.Logger.Error("removing maintenance status", "error", err)
+					return
+				}
+				maintenance = false
+			}
 			return
 		}
+		maintenance = false
 		l := len(b.Status)
 		rnd := rand.New(rand.NewPCG(uint64(time.Now().Unix()), uint64(l))).UintN(uint(l))
 		status := b.Status[rnd]
@@ -172,36 +541,32 @@ func (b *Bot) onReady(ctx context.Context, s bot.Session, _ *event.Ready) {
 	})
 }
 
+// AddHandler to the Bot, applied when the Bot is opened.
+// Prefer OnEvent (or a typed helper like OnMessageCreate) which checks the type of the handler at compile time.
 func (b *Bot) AddHandler(handler any) {
 	b.handlers = append(b.handlers, handler)
 }
 
-func (b *Bot) HandleModal(handler func(bot.Session, *event.InteractionCreate, *interaction.ModalSubmitData, *cmd.ResponseBuilder),
-	id string) {
-	b.AddHandler(func(_ context.Context, s bot.Session, i *event.InteractionCreate) {
-		if i.Type != types.InteractionModalSubmit {
-			return
-		}
+// HandleModal registers the handler called when the modal with the given custom ID is submitted
+func (b *Bot) HandleModal(handler cmd.ModalHandler, id string) {
+	b.Router().HandleModal(id, handler)
+}
 
-		data := i.ModalSubmitData()
-		if data.CustomID != id {
-			return
-		}
-		handler(s, i, data, cmd.NewResponseBuilder(s, i))
-	})
+// HandleMessageComponent registers the handler called when the message component with the given custom ID is used
+func (b *Bot) HandleMessageComponent(handler cmd.ComponentHandler, id string) {
+	b.Router().HandleComponent(id, handler)
 }
 
-func (b *Bot) HandleMessageComponent(handler func(bot.Session, *event.InteractionCreate, *interaction.MessageComponentData, *cmd.ResponseBuilder),
-	id string) {
-	b.AddHandler(func(_ context.Context, s bot.Session, i *event.InteractionCreate) {
-		if i.Type != types.InteractionMessageComponent {
-			return
-		}
+// HandleAutocomplete registers the handler called when the option of the command is autocompleted.
+// command is the full name of the command, including its subcommand (e.g. "config set").
+func (b *Bot) HandleAutocomplete(handler cmd.AutocompleteHandler, command string, option string) {
+	b.Router().HandleAutocomplete(command, option, handler)
+}
 
-		data := i.MessageComponentData()
-		if data.CustomID != id {
-			return
-		}
-		handler(s, i, data, cmd.NewResponseBuilder(s, i))
-	})
+// Router returns the cmd.Router used to dispatch message components, modals and autocompletions
+func (b *Bot) Router() *cmd.Router {
+	if b.router == nil {
+		b.router = cmd.NewRouter()
+	}
+	return b.router
 }
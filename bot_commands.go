@@ -2,6 +2,7 @@ package gokord
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"slices"
@@ -12,24 +13,18 @@ import (
 	"github.com/nyttikord/gokord/bot"
 	"github.com/nyttikord/gokord/discord/types"
 	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/guild"
 	"github.com/nyttikord/gokord/interaction"
 )
 
-var cmdMap map[string]cmd.CommandHandler = nil
-
 // updateCommands of the Bot
 func (b *Bot) updateCommands(s *discordgo.Session) {
-	// add ping command
-	b.Commands = append(
-		b.Commands,
-		cmd.New("ping", "Get the ping of the bot").
-			SetHandler(pingCommand).
-			AddContext(types.InteractionContextGuild).
-			AddContext(types.InteractionContextBotDM).
-			AddContext(types.InteractionContextPrivateChannel).
-			AddIntegrationType(types.IntegrationInstallGuild).
-			AddIntegrationType(types.IntegrationInstallUser),
-	)
+	// innovations are only applied to global commands, so they are kept for the next start without Bot.Debug
+	if b.Debug {
+		b.registerDevCommands(s)
+		return
+	}
+	b.cleanDevGuilds(s)
 
 	update, do := b.getCommandsUpdate()
 	if !do {
@@ -41,30 +36,24 @@ func (b *Bot) updateCommands(s *discordgo.Session) {
 	}
 
 	var wg sync.WaitGroup
-	// if Debug, avoid removing commands
-	if !Debug {
-		wg.Add(1)
-		go func() {
-			b.removeCommands(s, update.Commands)
-			wg.Done()
-		}()
-	}
-	wg.Add(1)
+	wg.Add(2)
+	go func() {
+		b.removeCommands(s, update.Commands)
+		wg.Done()
+	}()
 	go func() {
 		b.registerCommands(s, update.Commands)
 		wg.Done()
 	}()
 	wg.Wait()
+	b.syncUpdatedGuildCommands(s, update.Commands)
 	b.Version.UpdateBotVersion(b)
 	// sending changelog to guilds
 	if update.Changelog == "" {
 		return
 	}
-	for _, gID := range s.GuildAPI().State.Guilds() {
-		g, err := s.GuildAPI().State.Guild(gID)
-		if err != nil {
-			b.Logger.Error("getting guild", "error", err, "guild", gID)
-		} else if g.PublicUpdatesChannelID != "" {
+	for _, g := range b.changelogGuilds(s) {
+		if g.PublicUpdatesChannelID != "" {
 			changelog := fmt.Sprintf("## Nouveauté de la %s\n%s", update.Version, update.Changelog)
 			_, err := s.ChannelAPI().MessageSend(g.PublicUpdatesChannelID, changelog)
 			if err != nil {
@@ -74,9 +63,46 @@ func (b *Bot) updateCommands(s *discordgo.Session) {
 	}
 }
 
+// changelogGuilds returns the guilds receiving the changelog.
+// If the Bot is sharded, the state only contains the guilds of a shard: every guild is fetched with the API.
+func (b *Bot) changelogGuilds(s *discordgo.Session) []*guild.Guild {
+	var guilds []*guild.Guild
+	if b.Sharding == nil {
+		for _, gID := range s.GuildAPI().State.Guilds() {
+			g, err := s.GuildAPI().State.Guild(gID)
+			if err != nil {
+				b.Logger.Error("getting guild", "error", err, "guild", gID)
+				continue
+			}
+			guilds = append(guilds, g)
+		}
+		return guilds
+	}
+	after := ""
+	for {
+		ugs, err := s.GuildAPI().UserGuilds(200, "", after, false)
+		if err != nil {
+			b.Logger.Error("fetching guilds", "error", err)
+			return guilds
+		}
+		for _, ug := range ugs {
+			g, err := s.GuildAPI().Guild(ug.ID)
+			if err != nil {
+				b.Logger.Error("getting guild", "error", err, "guild", ug.ID)
+				continue
+			}
+			guilds = append(guilds, g)
+		}
+		if len(ugs) < 200 {
+			return guilds
+		}
+		after = ugs[len(ugs)-1].ID
+	}
+}
+
 // removeCommands delete commands of InnovationCommands.Removed
 func (b *Bot) removeCommands(s *discordgo.Session, update *InnovationCommands) {
-	appID := s.SessionState().User().ID
+	appID := b.applicationID(s)
 	cmdRegistered, err := s.InteractionAPI().Commands(appID, "")
 	if err != nil {
 		b.Logger.Error("fetching slash commands", "error", err)
@@ -101,54 +127,30 @@ func (b *Bot) removeCommands(s *discordgo.Session, update *InnovationCommands) {
 // registerCommands creates commands of InnovationCommands.Added and updates commands of InnovationCommands.Added
 func (b *Bot) registerCommands(s *discordgo.Session, update *InnovationCommands) {
 	var toUpdate []cmd.CommandBuilder
-	guildID := ""
-
-	// set toUpdate and guildID
-	if Debug {
-		gs := s.GuildAPI().State.Guilds()
-		if len(gs) == 0 {
-			b.Logger.Error("fetching guilds for debug", "error", fmt.Errorf("no cached guilds"))
-			return
-		}
-		guildID = gs[0]
-		// all commands (because it is in Debug)
-		toUpdate = b.Commands
-	} else {
-		for _, c := range append(update.Updated, update.Added...) {
-			id := slices.IndexFunc(b.Commands, func(e cmd.CommandBuilder) bool {
-				return c == e.GetName()
-			})
-			if id == -1 {
-				b.Logger.Warn("impossible to find command", "command", c)
-			} else {
-				toUpdate = append(toUpdate, b.Commands[id])
-			}
+	for _, c := range append(update.Updated, update.Added...) {
+		id := slices.IndexFunc(b.Commands, func(e cmd.CommandBuilder) bool {
+			return c == e.GetName()
+		})
+		if id == -1 {
+			b.Logger.Warn("impossible to find command", "command", c)
+		} else if b.Commands[id].GuildScoped() {
+			b.Logger.Debug("guild scoped command not registered globally", "command", c)
+		} else {
+			toUpdate = append(toUpdate, b.Commands[id])
 		}
 	}
 
 	// update everything needed
-	appID := s.SessionState().User().ID
+	appID := b.applicationID(s)
 	o := 0
-	if Debug {
-		for _, cb := range toUpdate {
-			registeredCommands = append(registeredCommands, cb.ApplicationCommand())
-		}
-		created, err := s.InteractionAPI().CommandBulkOverwrite(appID, guildID, registeredCommands)
+	for _, cb := range toUpdate {
+		c, err := s.InteractionAPI().CommandCreate(appID, "", cb.ApplicationCommand())
 		if err != nil {
-			b.Logger.Error("registering guild commands", "error", err)
-		}
-		registeredCommands = created
-		o = len(registeredCommands)
-	} else {
-		for _, cb := range toUpdate {
-			c, err := s.InteractionAPI().CommandCreate(appID, "", cb.ApplicationCommand())
-			if err != nil {
-				b.Logger.Error("registering command", "error", err, "command", cb.GetName())
-				continue
-			}
-			registeredCommands = append(registeredCommands, c)
-			o += 1
+			b.Logger.Error("registering command", "error", err, "command", cb.GetName())
+			continue
 		}
+		b.registeredCommands = append(b.registeredCommands, c)
+		o += 1
 	}
 	l := len(toUpdate)
 	var level slog.Level
@@ -160,55 +162,213 @@ func (b *Bot) registerCommands(s *discordgo.Session, update *InnovationCommands)
 	s.Logger().Log(context.Background(), level, "commands setups finished", "updated", o, "to update", l)
 }
 
+// applicationID returns the ID of the application (the ID of the user of the session if it was not fetched)
+func (b *Bot) applicationID(s bot.Session) string {
+	if b.appID != "" {
+		return b.appID
+	}
+	return s.SessionState().User().ID
+}
+
+// devGuilds returns the development guilds of Bot.Config
+func (b *Bot) devGuilds() []string {
+	if b.Config == nil {
+		return nil
+	}
+	return b.Config.GetDevGuilds()
+}
+
+// registerDevCommands registers every command in the development guilds (Bot.Debug = true only)
+func (b *Bot) registerDevCommands(s *discordgo.Session) {
+	guilds := b.devGuilds()
+	if len(guilds) == 0 {
+		b.Logger.Error("no development guilds in the config, commands are not registered")
+		return
+	}
+	cmds := make([]*interaction.Command, len(b.Commands))
+	for i, cb := range b.Commands {
+		cmds[i] = cb.ApplicationCommand()
+	}
+	appID := b.applicationID(s)
+	for _, guildID := range guilds {
+		created, err := s.InteractionAPI().CommandBulkOverwrite(appID, guildID, cmds)
+		if err != nil {
+			b.Logger.Error("registering guild commands", "error", err, "guild", guildID)
+			continue
+		}
+		b.registeredCommands = append(b.registeredCommands, created...)
+		b.Logger.Info("commands registered in development guild", "guild", guildID, "commands", len(created))
+	}
+}
+
 // setupCommandsHandlers of the Bot
 func (b *Bot) setupCommandsHandlers(s *discordgo.Session) {
-	if len(cmdMap) == 0 {
-		cmdMap = make(map[string]cmd.CommandHandler, len(b.Commands))
-		for _, c := range b.Commands {
-			b.Logger.Debug("setup handler", "command", c.GetName())
-			if c.HasSub() {
-				b.Logger.Debug("using general handler", "command", c.GetName())
-				cmdMap[c.GetName()] = b.generalHandler
-			} else {
-				cmdMap[c.GetName()] = c.GetHandler()
-			}
+	b.setupCommandMap()
+	newResp := b.responseFactory()
+	s.EventManager().AddHandler(func(ctx context.Context, s bot.Session, i *event.InteractionCreate) {
+		b.handleInteraction(s, i, func() *cmd.ResponseBuilder {
+			return newResp(s, i).SetContext(ctx)
+		})
+	})
+	if b.PrefixCommands != nil {
+		s.EventManager().AddHandler(b.onMessagePrefix)
+	}
+	if b.Debug {
+		s.EventManager().AddHandler(func(_ context.Context, s bot.Session, i *event.InteractionCreate) {
+			b.Logger.Debug("interaction received")
+			data, _ := json.Marshal(i)
+			b.Logger.Debug(string(data))
+		})
+	}
+}
+
+// setupCommandMap links the name of each command with its handler
+func (b *Bot) setupCommandMap() {
+	if len(b.cmdMap) > 0 {
+		return
+	}
+	b.cmdMap = make(map[string]cmd.CommandHandler, len(b.Commands))
+	for _, c := range b.Commands {
+		b.Logger.Debug("setup handler", "command", c.GetName())
+		if c.HasSub() {
+			b.Logger.Debug("using general handler", "command", c.GetName())
+			h := b.withCooldown(c.GetName(), c.GetCooldown(), b.generalHandler)
+			b.cmdMap[c.GetName()] = b.withGuards(c.GetName(), c.GetGuards(), h)
+		} else {
+			h := b.withCooldown(c.GetName(), c.GetCooldown(), c.GetHandler())
+			b.cmdMap[c.GetName()] = b.withGuards(c.GetName(), c.GetGuards(), h)
 		}
-		cmdMap["ping"] = pingCommand
 	}
-	s.EventManager().AddHandler(func(_ context.Context, s bot.Session, i *event.InteractionCreate) {
-		if i.Type != types.InteractionApplicationCommand {
+}
+
+// responseFactory returns a function creating the cmd.ResponseBuilder given to handlers
+func (b *Bot) responseFactory() func(s bot.Session, i *event.InteractionCreate) *cmd.ResponseBuilder {
+	router := b.Router()
+	branding := b.branding()
+	services := b.Services()
+	return func(s bot.Session, i *event.InteractionCreate) *cmd.ResponseBuilder {
+		return cmd.NewResponseBuilder(s, i).SetRouter(router).SetBranding(branding).SetServices(services)
+	}
+}
+
+// handleInteraction dispatches the interaction to the handler of the command, of the message component, of the modal
+// or of the autocompletion.
+// newResp creates the cmd.ResponseBuilder given to the handler.
+func (b *Bot) handleInteraction(s bot.Session, i *event.InteractionCreate, newResp func() *cmd.ResponseBuilder) {
+	if i.Type != types.InteractionApplicationCommandAutocomplete && b.isBlocked(s, i, newResp()) {
+		return
+	}
+	router := b.Router()
+	switch i.Type {
+	case types.InteractionApplicationCommand:
+		name := i.CommandData().Name
+		if h, ok := b.cmdMap[name]; ok {
+			b.runCommand(name, s, i, h, newResp())
+		}
+	case types.InteractionApplicationCommandAutocomplete:
+		name, focused := cmd.FocusedOption(i.CommandData())
+		if focused == nil {
 			return
 		}
-		if h, ok := cmdMap[i.CommandData().Name]; ok {
-			resp := cmd.NewResponseBuilder(s, i)
-			optMap := cmd.GenerateOptionMap(i)
-			h(s, i, optMap, resp)
+		if h, ok := router.Autocomplete(name, focused.Name); ok {
+			resp := newResp()
+			b.runHandler("autocomplete "+name+" "+focused.Name, i, resp, func() {
+				h(s, i, focused, resp)
+			})
 		}
-	})
+	case types.InteractionMessageComponent:
+		data := i.MessageComponentData()
+		if h, ok := router.Component(data.CustomID); ok {
+			resp := newResp()
+			b.runHandler("component "+data.CustomID, i, resp, func() {
+				h(s, i, data, resp)
+			})
+		}
+	case types.InteractionModalSubmit:
+		data := i.ModalSubmitData()
+		if h, ok := router.Modal(data.CustomID); ok {
+			resp := newResp()
+			b.runHandler("modal "+data.CustomID, i, resp, func() {
+				h(s, i, data, resp)
+			})
+		}
+	}
 }
 
-// unregisterGuildCommands used to unregister commands after closing the bot (Debug = true only)
-func (b *Bot) unregisterGuildCommands(s *discordgo.Session) {
-	if !Debug {
-		return
-	}
-	gs, err := s.GuildAPI().UserGuilds(1, "", "", false)
-	if err != nil {
-		b.Logger.Error("fetching guilds for debug", "error", err)
+// runHandler calls h, tracked to be drained when the Bot is closed, and recovers a panic.
+// If the Bot is shutting down, h is not called and the shutdown message is sent with resp.
+func (b *Bot) runHandler(name string, i *event.InteractionCreate, resp *cmd.ResponseBuilder, h func()) {
+	done, ok := b.track(name)
+	if !ok {
+		msg := cmd.LocalizedMessage(ShutdownMessages, string(i.Locale))
+		if err := resp.IsEphemeral().SetMessage(msg).Send(); err != nil {
+			b.Logger.Error("sending shutdown message", "error", err)
+		}
 		return
 	}
-	guildID := gs[0].ID
-	var wg sync.WaitGroup
-	for _, v := range registeredCommands {
-		wg.Add(1)
-		go func() {
-			err = s.InteractionAPI().CommandDelete(s.SessionState().User().ID, guildID, v.ID)
-			if err != nil {
-				b.Logger.Error("deleting command", "error", err, "name", v.Name, "id", v.ID)
+	defer done()
+	defer b.recoverHandler(name)
+	h()
+}
+
+// CommandMiddleware wraps the handler of every command (slash and prefix commands).
+// It must call next to run the command.
+type CommandMiddleware func(next cmd.CommandHandler) cmd.CommandHandler
+
+// UseCommandMiddleware adds CommandMiddleware applied to every command, in the order of their addition
+func (b *Bot) UseCommandMiddleware(m ...CommandMiddleware) {
+	b.commandMiddlewares = append(b.commandMiddlewares, m...)
+}
+
+// runCommand calls the handler of the command with runHandler, unless the command is disabled by the Maintenance.
+// The CommandMiddleware are applied.
+func (b *Bot) runCommand(name string, s bot.Session, i *event.InteractionCreate, h cmd.CommandHandler, resp *cmd.ResponseBuilder) {
+	b.runHandler("command "+name, i, resp, func() {
+		if msg, ok := b.maintenanceMessage(i); ok {
+			if err := resp.IsEphemeral().SetMessage(msg).Send(); err != nil {
+				b.Logger.Error("sending maintenance message", "error", err)
 			}
-			wg.Done()
-		}()
+			return
+		}
+		for _, m := range slices.Backward(b.commandMiddlewares) {
+			h = m(h)
+		}
+		optMap := cmd.GenerateOptionMap(i)
+		h(s, i, optMap, resp)
+	})
+}
+
+// cleanDevGuilds removes commands registered in the development guilds by registerDevCommands.
+// Guild scoped commands enabled in these guilds are registered again if Bot.Debug is false.
+func (b *Bot) cleanDevGuilds(s *discordgo.Session) {
+	appID := b.applicationID(s)
+	for _, guildID := range b.devGuilds() {
+		var err error
+		if !b.Debug && b.DB != nil {
+			err = b.SyncGuildCommands(s, guildID)
+		} else {
+			_, err = s.InteractionAPI().CommandBulkOverwrite(appID, guildID, []*interaction.Command{})
+		}
+		if err != nil {
+			b.Logger.Error("cleaning development guild commands", "error", err, "guild", guildID)
+		}
 	}
-	wg.Wait()
-	registeredCommands = []*interaction.Command{}
+	if b.Debug {
+		b.registeredCommands = []*interaction.Command{}
+	}
+}
+
+// branding returns a copy of the Branding of the Bot with the author of the Config
+func (b *Bot) branding() *cmd.Branding {
+	br := *cmd.DefaultBranding
+	if b.Branding != nil {
+		br = *b.Branding
+	}
+	if br.AuthorName == "" && b.Config != nil {
+		br.AuthorName = b.Config.GetAuthor()
+	}
+	if br.BotUser == nil {
+		br.BotUser = b.user
+	}
+	return &br
 }
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/nyttikord/gokord/channel"
+	"github.com/nyttikord/gokord/event"
+)
+
+var (
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrAttachmentTooLarge    = errors.New("attachment is too large")
+	ErrAttachmentContentType = errors.New("content type of the attachment is not allowed")
+	ErrDownloadingAttachment = errors.New("error while downloading attachment")
+)
+
+// Attachment is an attachment sent as an option of a command
+type Attachment struct {
+	*channel.MessageAttachment
+}
+
+// Attachment returns the Attachment given as the option with the given name
+func (o OptionMap) Attachment(i *event.InteractionCreate, name string) (*Attachment, error) {
+	opt, ok := o[name]
+	if !ok {
+		return nil, ErrAttachmentNotFound
+	}
+	id, ok := opt.Value.(string)
+	if !ok {
+		return nil, ErrAttachmentNotFound
+	}
+	data := i.CommandData()
+	if data.Resolved == nil {
+		return nil, ErrAttachmentNotFound
+	}
+	a, ok := data.Resolved.Attachments[id]
+	if !ok {
+		return nil, ErrAttachmentNotFound
+	}
+	return &Attachment{MessageAttachment: a}, nil
+}
+
+// Check the content type and the size of the Attachment.
+//
+// contentTypes are the allowed content types: a content type ending with "/" is a prefix (e.g., "image/" allows every
+// image).
+// If contentTypes is empty, every content type is allowed.
+// If maxSize is 0, there is no size limit.
+func (a *Attachment) Check(contentTypes []string, maxSize int) error {
+	if maxSize > 0 && a.Size > maxSize {
+		return ErrAttachmentTooLarge
+	}
+	if len(contentTypes) == 0 {
+		return nil
+	}
+	ct, _, _ := strings.Cut(a.ContentType, ";")
+	for _, allowed := range contentTypes {
+		if ct == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(ct, allowed)) {
+			return nil
+		}
+	}
+	return ErrAttachmentContentType
+}
+
+// Open the Attachment to read it while it is downloaded.
+// Reading more than maxSize bytes returns ErrAttachmentTooLarge (no limit if maxSize is 0).
+//
+// The returned io.ReadCloser must be closed.
+func (a *Attachment) Open(ctx context.Context, maxSize int) (io.ReadCloser, error) {
+	if maxSize > 0 && a.Size > maxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Join(ErrDownloadingAttachment, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, errors.Join(ErrDownloadingAttachment, fmt.Errorf("status code %d", resp.StatusCode))
+	}
+	if maxSize > 0 && resp.ContentLength > int64(maxSize) {
+		_ = resp.Body.Close()
+		return nil, ErrAttachmentTooLarge
+	}
+	if maxSize <= 0 {
+		return resp.Body, nil
+	}
+	return &limitedReadCloser{rc: resp.Body, remaining: int64(maxSize)}, nil
+}
+
+// Download the Attachment in memory.
+// It returns ErrAttachmentTooLarge if the attachment is bigger than maxSize (no limit if maxSize is 0).
+func (a *Attachment) Download(ctx context.Context, maxSize int) ([]byte, error) {
+	rc, err := a.Open(ctx, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// limitedReadCloser returns ErrAttachmentTooLarge if more than remaining bytes are read
+type limitedReadCloser struct {
+	rc        io.ReadCloser
+	remaining int64
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) {
+	// reading one more byte to detect if the limit is exceeded
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.rc.Read(p)
+	if int64(n) > l.remaining {
+		n = int(l.remaining)
+		l.remaining = 0
+		return n, ErrAttachmentTooLarge
+	}
+	l.remaining -= int64(n)
+	return n, err
+}
+
+func (l *limitedReadCloser) Close() error {
+	return l.rc.Close()
+}
+
+type FileBuilder interface {
+	// SetContentType of the FileBuilder
+	SetContentType(ct string) FileBuilder
+	// SetDescription of the FileBuilder (alt text)
+	SetDescription(d string) FileBuilder
+	// IsSpoiler informs that the FileBuilder is a spoiler
+	IsSpoiler() FileBuilder
+	toFile() (*channel.File, string)
+}
+
+// fileCreator represents a generic file sent in a response
+type fileCreator struct {
+	Name        string
+	ContentType string
+	Description string
+	Spoiler     bool
+	Reader      io.Reader
+}
+
+// NewFile creates a new FileBuilder read from r when the response is sent
+func NewFile(name string, r io.Reader) FileBuilder {
+	return &fileCreator{
+		Name:   name,
+		Reader: r,
+	}
+}
+
+// SetContentType of the fileCreator
+func (f *fileCreator) SetContentType(ct string) FileBuilder {
+	f.ContentType = ct
+	return f
+}
+
+// SetDescription of the fileCreator
+func (f *fileCreator) SetDescription(d string) FileBuilder {
+	f.Description = d
+	return f
+}
+
+// IsSpoiler informs that the fileCreator is a spoiler
+func (f *fileCreator) IsSpoiler() FileBuilder {
+	f.Spoiler = true
+	return f
+}
+
+// toFile turns fileCreator into a channel.File and returns its description
+func (f *fileCreator) toFile() (*channel.File, string) {
+	name := f.Name
+	if f.Spoiler && !strings.HasPrefix(name, "SPOILER_") {
+		name = "SPOILER_" + name
+	}
+	return &channel.File{
+		Name:        name,
+		ContentType: f.ContentType,
+		Reader:      f.Reader,
+	}, f.Description
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/channel"
+	"github.com/nyttikord/gokord/user"
+)
+
+// DefaultBranding is the Branding used when none is configured
+var DefaultBranding = &Branding{
+	Footer:     "by {author}",
+	FooterIcon: true,
+	Author:     true,
+	Timestamp:  true,
+}
+
+// Branding is applied to every embed added with ResponseBuilder.AddEmbed.
+// It only fills the fields that are not already set by the embed.
+type Branding struct {
+	// Footer is the template of the footer text.
+	// {author} is replaced by AuthorName, and {bot} by the username of the bot.
+	// Empty to disable it.
+	Footer string
+	// AuthorName is the author (or the owner) of the bot
+	AuthorName string
+	// FooterIcon uses the avatar of the bot as the icon of the footer
+	FooterIcon bool
+	// Author uses the username of the bot as the author of the embed
+	Author bool
+	// Timestamp sets the timestamp to the current time
+	Timestamp bool
+	// Color returns the color of embeds sent in the guild (guildID is empty in DMs).
+	// If nil or if it returns 0, the color is not modified
+	Color func(guildID string) int
+	// BotUser is used instead of the user of the session (set by gokord for HTTP interactions, where the session has no
+	// user)
+	BotUser *user.User
+}
+
+// apply the Branding to the embed
+func (b *Branding) apply(s bot.Session, guildID string, e *channel.MessageEmbed) {
+	u := b.BotUser
+	if u == nil {
+		u = s.SessionState().User()
+	}
+	if u == nil {
+		u = &user.User{}
+	}
+	if e.Footer == nil && b.Footer != "" {
+		author := b.AuthorName
+		if author == "" {
+			author = Author
+		}
+		e.Footer = &channel.MessageEmbedFooter{
+			Text: strings.NewReplacer("{author}", author, "{bot}", u.Username).Replace(b.Footer),
+		}
+		if b.FooterIcon && u.ID != "" {
+			e.Footer.IconURL = u.AvatarURL("")
+		}
+	}
+	if e.Author == nil && b.Author && u.Username != "" {
+		e.Author = &channel.MessageEmbedAuthor{Name: u.Username}
+	}
+	if e.Timestamp == "" && b.Timestamp {
+		e.Timestamp = time.Now().Format(time.RFC3339)
+	}
+	if e.Color == 0 && b.Color != nil {
+		e.Color = b.Color(guildID)
+	}
+}
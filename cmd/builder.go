@@ -28,6 +28,13 @@ type CommandBuilder interface {
 	AddIntegrationType(ctx types.IntegrationInstall) CommandBuilder
 	// SetPermission of the CommandBuilder
 	SetPermission(p *int64) CommandBuilder
+	// SetCooldown of the CommandBuilder (works with subcommands)
+	SetCooldown(c *Cooldown) CommandBuilder
+	// AddGuard to the CommandBuilder, checked before calling the handler (guards of a command are also checked for
+	// its subcommands)
+	AddGuard(g ...Guard) CommandBuilder
+	// IsGuildScoped informs that the CommandBuilder is only registered in guilds which enabled it
+	IsGuildScoped() CommandBuilder
 	// GetName returns the name of the command
 	GetName() string
 	// HasSub returns true if the command has subcommands
@@ -36,6 +43,12 @@ type CommandBuilder interface {
 	GetHandler() CommandHandler
 	// GetSubs returns subcommands
 	GetSubs() []CommandBuilder
+	// GetCooldown returns the Cooldown of the command (nil if there is no Cooldown)
+	GetCooldown() *Cooldown
+	// GetGuards returns the Guard of the command
+	GetGuards() []Guard
+	// GuildScoped returns true if the command is only registered in guilds which enabled it
+	GuildScoped() bool
 	// ApplicationCommand returns the application command understandable by Discord
 	ApplicationCommand() *interaction.Command
 	setSub(bool)
@@ -45,8 +58,20 @@ type CommandBuilder interface {
 type CommandOptionBuilder interface {
 	// IsRequired informs that the CommandOptionBuilder is required
 	IsRequired() CommandOptionBuilder
-	// AddChoice to the CommandOptionBuilder
+	// NotRequired informs that the CommandOptionBuilder is optional (default, except after AddChoice)
+	NotRequired() CommandOptionBuilder
+	// AddChoice to the CommandOptionBuilder (it becomes required, see NotRequired)
 	AddChoice(ch CommandChoiceBuilder) CommandOptionBuilder
+	// SetMinValue of the integer or number CommandOptionBuilder
+	SetMinValue(v float64) CommandOptionBuilder
+	// SetMaxValue of the integer or number CommandOptionBuilder
+	SetMaxValue(v float64) CommandOptionBuilder
+	// SetMinLength of the string CommandOptionBuilder
+	SetMinLength(l int) CommandOptionBuilder
+	// SetMaxLength of the string CommandOptionBuilder
+	SetMaxLength(l int) CommandOptionBuilder
+	// IsAutocomplete informs that the choices are sent by the AutocompleteHandler registered in the Router
+	IsAutocomplete() CommandOptionBuilder
 	toDiscordOption() *interaction.CommandOption
 }
 
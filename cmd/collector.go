@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/interaction"
+)
+
+var (
+	ErrCollectorTimeout = errors.New("timed out while waiting for an interaction")
+	ErrCollectorClosed  = errors.New("collector closed")
+)
+
+// Collected is an interaction received by a Collector
+type Collected[T any] struct {
+	Interaction *event.InteractionCreate
+	Data        T
+	Response    *ResponseBuilder // Response to the collected interaction
+}
+
+// Collector waits for interactions on components or modals from inside a handler.
+// It only accepts interactions created by the user who invoked the handler.
+//
+// Always call Close when the Collector is no longer used to unregister its handlers.
+type Collector[T any] struct {
+	ch     chan *Collected[T]
+	closed chan struct{}
+	once   sync.Once
+	remove func()
+}
+
+// CollectComponents creates a Collector for the message components with the given custom IDs.
+// It must be created before sending the components.
+func CollectComponents(resp *ResponseBuilder, ids ...string) (*Collector[*interaction.MessageComponentData], error) {
+	if resp.router == nil {
+		return nil, ErrRouterNotSet
+	}
+	c := newCollector[*interaction.MessageComponentData]()
+	userID := InteractionUserID(resp.interaction)
+	handler := func(s bot.Session, i *event.InteractionCreate, data *interaction.MessageComponentData, r *ResponseBuilder) {
+		if InteractionUserID(i) != userID {
+			if err := r.IsEphemeral().SetMessage("You cannot use this component.").Send(); err != nil {
+				s.Logger().Error("sending collector forbidden message", "error", err)
+			}
+			return
+		}
+		c.collect(&Collected[*interaction.MessageComponentData]{Interaction: i, Data: data, Response: r})
+	}
+	for _, id := range ids {
+		resp.router.HandleComponent(id, handler)
+	}
+	c.remove = func() {
+		for _, id := range ids {
+			resp.router.RemoveComponent(id)
+		}
+	}
+	return c, nil
+}
+
+// CollectModal creates a Collector for the modal with the given custom ID.
+// It must be created before sending the modal.
+func CollectModal(resp *ResponseBuilder, id string) (*Collector[*interaction.ModalSubmitData], error) {
+	if resp.router == nil {
+		return nil, ErrRouterNotSet
+	}
+	c := newCollector[*interaction.ModalSubmitData]()
+	userID := InteractionUserID(resp.interaction)
+	resp.router.HandleModal(id, func(_ bot.Session, i *event.InteractionCreate, data *interaction.ModalSubmitData, r *ResponseBuilder) {
+		if InteractionUserID(i) != userID {
+			return
+		}
+		c.collect(&Collected[*interaction.ModalSubmitData]{Interaction: i, Data: data, Response: r})
+	})
+	c.remove = func() {
+		resp.router.RemoveModal(id)
+	}
+	return c, nil
+}
+
+func newCollector[T any]() *Collector[T] {
+	return &Collector[T]{
+		ch:     make(chan *Collected[T], 1),
+		closed: make(chan struct{}),
+	}
+}
+
+// collect the interaction if someone is waiting for it
+func (c *Collector[T]) collect(col *Collected[T]) {
+	select {
+	case c.ch <- col:
+	default:
+		// an interaction is already waiting to be read, this one is dropped but answered to avoid an error
+		if err := col.Response.acknowledge(); err != nil {
+			col.Response.session.Logger().Error("acknowledging dropped interaction", "error", err)
+		}
+	}
+}
+
+// Wait for the next interaction.
+// It returns ErrCollectorTimeout if nothing was received before the timeout.
+func (c *Collector[T]) Wait(timeout time.Duration) (*Collected[T], error) {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case col := <-c.ch:
+		return col, nil
+	case <-t.C:
+		return nil, ErrCollectorTimeout
+	case <-c.closed:
+		return nil, ErrCollectorClosed
+	}
+}
+
+// Close the Collector and unregister its handlers
+func (c *Collector[T]) Close() {
+	c.once.Do(func() {
+		c.remove()
+		close(c.closed)
+		// the interaction waiting to be read is answered
+		select {
+		case col := <-c.ch:
+			if err := col.Response.acknowledge(); err != nil {
+				col.Response.session.Logger().Error("acknowledging dropped interaction", "error", err)
+			}
+		default:
+		}
+	})
+}
+
+// Confirm is a "Yes / No" dialog
+type Confirm struct {
+	message string
+	yes     string
+	no      string
+	timeout time.Duration
+}
+
+// NewConfirm creates a new Confirm dialog displaying the given message
+func NewConfirm(msg string) *Confirm {
+	return &Confirm{
+		message: msg,
+		yes:     "Yes",
+		no:      "No",
+		timeout: time.Minute,
+	}
+}
+
+// SetLabels of the buttons
+func (c *Confirm) SetLabels(yes string, no string) *Confirm {
+	c.yes = yes
+	c.no = no
+	return c
+}
+
+// SetTimeout sets the duration to wait for the answer
+func (c *Confirm) SetTimeout(d time.Duration) *Confirm {
+	c.timeout = d
+	return c
+}
+
+// Ask sends the dialog as the response and waits for the answer of the user.
+//
+// It returns the answer and the ResponseBuilder responding to the click.
+// This ResponseBuilder is in update mode, and it already contains the disabled buttons: you just have to set a new
+// message and to send it.
+//
+// If the user did not answer in time, the buttons are disabled and ErrCollectorTimeout is returned.
+func (c *Confirm) Ask(resp *ResponseBuilder) (bool, *ResponseBuilder, error) {
+	yesID := fmt.Sprintf("gokord:confirm:%s:yes", resp.interaction.ID)
+	noID := fmt.Sprintf("gokord:confirm:%s:no", resp.interaction.ID)
+	col, err := CollectComponents(resp, yesID, noID)
+	if err != nil {
+		return false, nil, err
+	}
+	defer col.Close()
+
+	err = resp.SetMessage(c.message).AddComponent(c.buttons(yesID, noID, false)).Send()
+	if err != nil {
+		return false, nil, err
+	}
+	got, err := col.Wait(c.timeout)
+	if err != nil {
+		edit := resp.new().
+			IsEdit().
+			SetMessage(c.message).
+			AddComponent(c.buttons(yesID, noID, true))
+		if errEdit := edit.Send(); errEdit != nil {
+			resp.session.Logger().Error("disabling confirm dialog", "error", errEdit)
+		}
+		return false, nil, err
+	}
+	return got.Data.CustomID == yesID, got.Response.IsUpdate().AddComponent(c.buttons(yesID, noID, true)), nil
+}
+
+func (c *Confirm) buttons(yesID string, noID string, disabled bool) ActionRowBuilder {
+	yes := NewButton(yesID, c.yes, types.ButtonStyleDanger)
+	no := NewButton(noID, c.no, types.ButtonStyleSecondary)
+	if disabled {
+		yes.IsDisabled()
+		no.IsDisabled()
+	}
+	return NewActionRow().AddComponent(yes).AddComponent(no)
+}
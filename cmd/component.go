@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/nyttikord/gokord/component"
+	"github.com/nyttikord/gokord/discord/types"
+)
+
+const (
+	MaxActionRows       = 5  // MaxActionRows in a message without layout components
+	MaxActionRowWidth   = 5  // MaxActionRowWidth is the number of buttons that an action row can contain
+	MaxSelectOptions    = 25 // MaxSelectOptions in a string select menu
+	MaxLayoutComponents = 40 // MaxLayoutComponents in a message using layout components (nested ones are counted)
+	MaxSectionTexts     = 3  // MaxSectionTexts in a section
+	MaxGalleryItems     = 10 // MaxGalleryItems in a media gallery
+	MaxCustomIDLength   = 100
+)
+
+var (
+	ErrTooManyActionRows      = errors.New("too many action rows in the message")
+	ErrTooManyComponents      = errors.New("too many components in the message")
+	ErrActionRowFull          = errors.New("action row cannot contain this component, it is full")
+	ErrEmptyActionRow         = errors.New("action row is empty")
+	ErrInvalidCustomID        = errors.New("custom ID must be between 1 and 100 characters long")
+	ErrInvalidURL             = errors.New("url is empty")
+	ErrTooManyOptions         = errors.New("too many options in select menu")
+	ErrNoOptions              = errors.New("string select menu does not have any option")
+	ErrInvalidMinMaxValues    = errors.New("invalid min or max values in select menu")
+	ErrInvalidSectionTexts    = errors.New("section must contain between 1 and 3 texts")
+	ErrMissingAccessory       = errors.New("section does not have an accessory")
+	ErrInvalidGalleryItems    = errors.New("media gallery must contain between 1 and 10 items")
+	ErrEmptyContainer         = errors.New("container is empty")
+	ErrNotMessageComponent    = errors.New("component cannot be sent in a message")
+	ErrLayoutWithContent      = errors.New("message with layout components cannot have content or embeds")
+	ErrRouterNotSet           = errors.New("router is not set, impossible to bind handlers")
+	ErrHandlerWithoutCustomID = errors.New("component with a handler must have a custom ID")
+)
+
+// ComponentBuilder is a top-level component of a message
+type ComponentBuilder interface {
+	// Component returns the component understandable by Discord
+	Component() (component.Message, error)
+	// IsLayout returns true if the component requires the flag channel.MessageFlagsIsComponentsV2
+	IsLayout() bool
+	// bind registers handlers of the component (and of its children) in the Router and returns their custom IDs
+	bind(r *Router) []string
+	// size returns the number of components, including nested ones
+	size() int
+}
+
+// InteractiveComponentBuilder is a component that can be added in an ActionRowBuilder
+type InteractiveComponentBuilder interface {
+	// GetCustomID returns the custom ID of the component
+	GetCustomID() string
+	toComponent() (component.Component, error)
+	bind(r *Router) []string
+	// width returns the space taken in an action row (MaxActionRowWidth is the full row)
+	width() int
+}
+
+// AccessoryBuilder is a component that can be used as the accessory of a SectionBuilder
+type AccessoryBuilder interface {
+	toAccessory() (component.Component, error)
+	bind(r *Router) []string
+}
+
+type ActionRowBuilder interface {
+	ComponentBuilder
+	// AddComponent to the ActionRowBuilder.
+	// It can contain up to 5 ButtonBuilder, or only one SelectMenuBuilder or TextInputBuilder
+	AddComponent(c InteractiveComponentBuilder) ActionRowBuilder
+}
+
+type ButtonBuilder interface {
+	InteractiveComponentBuilder
+	AccessoryBuilder
+	// SetEmoji of the ButtonBuilder
+	SetEmoji(e *component.Emoji) ButtonBuilder
+	// IsDisabled informs that the ButtonBuilder is disabled
+	IsDisabled() ButtonBuilder
+	// SetHandler called when the button is clicked (the custom ID is registered automatically).
+	// It stays registered until ResponseBuilder.RemoveHandlers is called or until it expires (see
+	// ResponseBuilder.ExpireHandlers).
+	SetHandler(handler ComponentHandler) ButtonBuilder
+}
+
+type SelectMenuBuilder interface {
+	InteractiveComponentBuilder
+	// SetPlaceholder of the SelectMenuBuilder
+	SetPlaceholder(p string) SelectMenuBuilder
+	// SetMinValues is the minimum number of items that must be chosen
+	SetMinValues(n int) SelectMenuBuilder
+	// SetMaxValues is the maximum number of items that can be chosen
+	SetMaxValues(n int) SelectMenuBuilder
+	// AddOption to the SelectMenuBuilder (only for string select menus)
+	AddOption(o SelectOptionBuilder) SelectMenuBuilder
+	// AddChannelType filters the channels that can be chosen (only for channel select menus)
+	AddChannelType(t types.Channel) SelectMenuBuilder
+	// AddDefaultValue selected by default (only for user, role, mentionable and channel select menus)
+	AddDefaultValue(id string, t types.SelectMenuDefaultValue) SelectMenuBuilder
+	// IsDisabled informs that the SelectMenuBuilder is disabled
+	IsDisabled() SelectMenuBuilder
+	// SetHandler called when values are selected (the custom ID is registered automatically).
+	// It stays registered until ResponseBuilder.RemoveHandlers is called or until it expires (see
+	// ResponseBuilder.ExpireHandlers).
+	SetHandler(handler ComponentHandler) SelectMenuBuilder
+}
+
+type SelectOptionBuilder interface {
+	// SetDescription of the SelectOptionBuilder
+	SetDescription(d string) SelectOptionBuilder
+	// SetEmoji of the SelectOptionBuilder
+	SetEmoji(e *component.Emoji) SelectOptionBuilder
+	// IsDefault informs that the SelectOptionBuilder is selected by default
+	IsDefault() SelectOptionBuilder
+	toDiscordOption() component.SelectMenuOption
+}
+
+type TextInputBuilder interface {
+	InteractiveComponentBuilder
+	// SetPlaceholder of the TextInputBuilder
+	SetPlaceholder(p string) TextInputBuilder
+	// SetValue pre-filled
+	SetValue(v string) TextInputBuilder
+	// IsRequired informs that the TextInputBuilder is required
+	IsRequired() TextInputBuilder
+	// SetLength sets the minimum and the maximum length of the input
+	SetLength(min, max int) TextInputBuilder
+}
+
+type TextDisplayBuilder interface {
+	ComponentBuilder
+}
+
+type SectionBuilder interface {
+	ComponentBuilder
+	// AddText to the SectionBuilder (up to 3)
+	AddText(content string) SectionBuilder
+	// SetAccessory of the SectionBuilder (a ButtonBuilder or a ThumbnailBuilder)
+	SetAccessory(a AccessoryBuilder) SectionBuilder
+}
+
+type ThumbnailBuilder interface {
+	AccessoryBuilder
+	// SetDescription of the ThumbnailBuilder (alt text)
+	SetDescription(d string) ThumbnailBuilder
+	// IsSpoiler informs that the ThumbnailBuilder is a spoiler
+	IsSpoiler() ThumbnailBuilder
+}
+
+type MediaGalleryBuilder interface {
+	ComponentBuilder
+	// AddItem to the MediaGalleryBuilder (up to 10).
+	// description may be empty
+	AddItem(url string, description string, spoiler bool) MediaGalleryBuilder
+}
+
+type FileComponentBuilder interface {
+	ComponentBuilder
+	// IsSpoiler informs that the FileComponentBuilder is a spoiler
+	IsSpoiler() FileComponentBuilder
+}
+
+type SeparatorBuilder interface {
+	ComponentBuilder
+	// WithoutDivider hides the line of the SeparatorBuilder
+	WithoutDivider() SeparatorBuilder
+	// SetSpacing of the SeparatorBuilder
+	SetSpacing(s types.SeparatorSpacing) SeparatorBuilder
+}
+
+type ContainerBuilder interface {
+	ComponentBuilder
+	// AddComponent to the ContainerBuilder
+	AddComponent(c ComponentBuilder) ContainerBuilder
+	// SetAccentColor of the ContainerBuilder
+	SetAccentColor(color int) ContainerBuilder
+	// IsSpoiler informs that the ContainerBuilder is a spoiler
+	IsSpoiler() ContainerBuilder
+}
+
+// NewActionRow creates a new ActionRowBuilder
+func NewActionRow() ActionRowBuilder {
+	return &actionRowCreator{Components: []InteractiveComponentBuilder{}}
+}
+
+// NewButton creates a new ButtonBuilder.
+// Use NewLinkButton to create a button with the types.ButtonStyleLink style
+func NewButton(customID string, label string, style types.ButtonStyle) ButtonBuilder {
+	return &buttonCreator{
+		CustomID: customID,
+		Label:    label,
+		Style:    style,
+	}
+}
+
+// NewLinkButton creates a new ButtonBuilder opening the given url
+func NewLinkButton(url string, label string) ButtonBuilder {
+	return &buttonCreator{
+		URL:   url,
+		Label: label,
+		Style: types.ButtonStyleLink,
+	}
+}
+
+// NewStringSelect creates a new SelectMenuBuilder with options defined by AddOption
+func NewStringSelect(customID string) SelectMenuBuilder {
+	return newSelect(customID, types.SelectMenuString)
+}
+
+// NewUserSelect creates a new SelectMenuBuilder to choose users
+func NewUserSelect(customID string) SelectMenuBuilder {
+	return newSelect(customID, types.SelectMenuUser)
+}
+
+// NewRoleSelect creates a new SelectMenuBuilder to choose roles
+func NewRoleSelect(customID string) SelectMenuBuilder {
+	return newSelect(customID, types.SelectMenuRole)
+}
+
+// NewMentionableSelect creates a new SelectMenuBuilder to choose users and roles
+func NewMentionableSelect(customID string) SelectMenuBuilder {
+	return newSelect(customID, types.SelectMenuMentionable)
+}
+
+// NewChannelSelect creates a new SelectMenuBuilder to choose channels
+func NewChannelSelect(customID string) SelectMenuBuilder {
+	return newSelect(customID, types.SelectMenuChannel)
+}
+
+func newSelect(customID string, t types.SelectMenu) *selectMenuCreator {
+	return &selectMenuCreator{
+		MenuType:  t,
+		CustomID:  customID,
+		MinValues: 1,
+		MaxValues: 1,
+	}
+}
+
+// NewSelectOption creates a new SelectOptionBuilder for SelectMenuBuilder
+func NewSelectOption(label string, value string) SelectOptionBuilder {
+	return &selectOptionCreator{
+		Label: label,
+		Value: value,
+	}
+}
+
+// NewTextInput creates a new TextInputBuilder for modals
+func NewTextInput(customID string, label string, style types.TextInputStyle) TextInputBuilder {
+	return &textInputCreator{
+		CustomID: customID,
+		Label:    label,
+		Style:    style,
+	}
+}
+
+// NewTextDisplay creates a new TextDisplayBuilder (layout component)
+func NewTextDisplay(content string) TextDisplayBuilder {
+	return &textDisplayCreator{Content: content}
+}
+
+// NewSection creates a new SectionBuilder (layout component)
+func NewSection() SectionBuilder {
+	return &sectionCreator{Texts: []string{}}
+}
+
+// NewThumbnail creates a new ThumbnailBuilder for SectionBuilder
+func NewThumbnail(url string) ThumbnailBuilder {
+	return &thumbnailCreator{URL: url}
+}
+
+// NewMediaGallery creates a new MediaGalleryBuilder (layout component)
+func NewMediaGallery() MediaGalleryBuilder {
+	return &mediaGalleryCreator{Items: []component.MediaGalleryItem{}}
+}
+
+// NewFileComponent creates a new FileComponentBuilder (layout component).
+// url must use the attachment://filename syntax
+func NewFileComponent(url string) FileComponentBuilder {
+	return &fileComponentCreator{URL: url}
+}
+
+// NewSeparator creates a new SeparatorBuilder (layout component)
+func NewSeparator() SeparatorBuilder {
+	return &separatorCreator{Divider: true}
+}
+
+// NewContainer creates a new ContainerBuilder (layout component)
+func NewContainer() ContainerBuilder {
+	return &containerCreator{Components: []ComponentBuilder{}}
+}
@@ -0,0 +1,635 @@
+package cmd
+
+import (
+	"github.com/nyttikord/gokord/component"
+	"github.com/nyttikord/gokord/discord/types"
+)
+
+// actionRowCreator represents a generic action row
+type actionRowCreator struct {
+	Components []InteractiveComponentBuilder
+}
+
+// buttonCreator represents a generic button
+type buttonCreator struct {
+	CustomID string
+	Label    string
+	Style    types.ButtonStyle
+	URL      string
+	Emoji    *component.Emoji
+	Disabled bool
+	Handler  ComponentHandler
+}
+
+// selectMenuCreator represents a generic select menu
+type selectMenuCreator struct {
+	MenuType      types.SelectMenu
+	CustomID      string
+	Placeholder   string
+	MinValues     int
+	MaxValues     int
+	Options       []SelectOptionBuilder
+	ChannelTypes  []types.Channel
+	DefaultValues []component.SelectMenuDefaultValue
+	Disabled      bool
+	Handler       ComponentHandler
+}
+
+// selectOptionCreator represents a generic option of selectMenuCreator
+type selectOptionCreator struct {
+	Label       string
+	Value       string
+	Description string
+	Emoji       *component.Emoji
+	Default     bool
+}
+
+// textInputCreator represents a generic text input of a modal
+type textInputCreator struct {
+	CustomID    string
+	Label       string
+	Style       types.TextInputStyle
+	Placeholder string
+	Value       string
+	Required    bool
+	MinLength   int
+	MaxLength   int
+}
+
+// textDisplayCreator represents a generic text display
+type textDisplayCreator struct {
+	Content string
+}
+
+// sectionCreator represents a generic section
+type sectionCreator struct {
+	Texts     []string
+	Accessory AccessoryBuilder
+}
+
+// thumbnailCreator represents a generic thumbnail
+type thumbnailCreator struct {
+	URL         string
+	Description string
+	Spoiler     bool
+}
+
+// mediaGalleryCreator represents a generic media gallery
+type mediaGalleryCreator struct {
+	Items []component.MediaGalleryItem
+}
+
+// fileComponentCreator represents a generic file component
+type fileComponentCreator struct {
+	URL     string
+	Spoiler bool
+}
+
+// separatorCreator represents a generic separator
+type separatorCreator struct {
+	Divider bool
+	Spacing *types.SeparatorSpacing
+}
+
+// containerCreator represents a generic container
+type containerCreator struct {
+	Components  []ComponentBuilder
+	AccentColor *int
+	Spoiler     bool
+}
+
+func validCustomID(id string) bool {
+	return len(id) > 0 && len(id) <= MaxCustomIDLength
+}
+
+// AddComponent to the actionRowCreator
+func (a *actionRowCreator) AddComponent(c InteractiveComponentBuilder) ActionRowBuilder {
+	a.Components = append(a.Components, c)
+	return a
+}
+
+func (a *actionRowCreator) IsLayout() bool {
+	return false
+}
+
+// Component turns actionRowCreator into a component.ActionsRow
+func (a *actionRowCreator) Component() (component.Message, error) {
+	if len(a.Components) == 0 {
+		return nil, ErrEmptyActionRow
+	}
+	w := 0
+	cmps := make([]component.Component, len(a.Components))
+	for i, c := range a.Components {
+		w += c.width()
+		if w > MaxActionRowWidth {
+			return nil, ErrActionRowFull
+		}
+		cmp, err := c.toComponent()
+		if err != nil {
+			return nil, err
+		}
+		cmps[i] = cmp
+	}
+	return &component.ActionsRow{Components: cmps}, nil
+}
+
+func (a *actionRowCreator) bind(r *Router) []string {
+	var ids []string
+	for _, c := range a.Components {
+		ids = append(ids, c.bind(r)...)
+	}
+	return ids
+}
+
+func (a *actionRowCreator) size() int {
+	return len(a.Components) + 1
+}
+
+func (b *buttonCreator) GetCustomID() string {
+	return b.CustomID
+}
+
+// SetEmoji of the buttonCreator
+func (b *buttonCreator) SetEmoji(e *component.Emoji) ButtonBuilder {
+	b.Emoji = e
+	return b
+}
+
+// IsDisabled informs that the buttonCreator is disabled
+func (b *buttonCreator) IsDisabled() ButtonBuilder {
+	b.Disabled = true
+	return b
+}
+
+// SetHandler called when the buttonCreator is clicked
+func (b *buttonCreator) SetHandler(handler ComponentHandler) ButtonBuilder {
+	b.Handler = handler
+	return b
+}
+
+// toComponent turns buttonCreator into a component.Button
+func (b *buttonCreator) toComponent() (component.Component, error) {
+	btn := &component.Button{
+		Label:    b.Label,
+		Style:    b.Style,
+		Disabled: b.Disabled,
+		Emoji:    b.Emoji,
+	}
+	if b.Style == types.ButtonStyleLink {
+		if b.URL == "" {
+			return nil, ErrInvalidURL
+		}
+		if b.Handler != nil {
+			return nil, ErrHandlerWithoutCustomID
+		}
+		btn.URL = b.URL
+		return btn, nil
+	}
+	if !validCustomID(b.CustomID) {
+		return nil, ErrInvalidCustomID
+	}
+	btn.CustomID = b.CustomID
+	return btn, nil
+}
+
+func (b *buttonCreator) toAccessory() (component.Component, error) {
+	return b.toComponent()
+}
+
+func (b *buttonCreator) bind(r *Router) []string {
+	if b.Handler == nil {
+		return nil
+	}
+	r.HandleComponent(b.CustomID, b.Handler)
+	return []string{b.CustomID}
+}
+
+func (b *buttonCreator) width() int {
+	return 1
+}
+
+func (s *selectMenuCreator) GetCustomID() string {
+	return s.CustomID
+}
+
+// SetPlaceholder of the selectMenuCreator
+func (s *selectMenuCreator) SetPlaceholder(p string) SelectMenuBuilder {
+	s.Placeholder = p
+	return s
+}
+
+// SetMinValues of the selectMenuCreator
+func (s *selectMenuCreator) SetMinValues(n int) SelectMenuBuilder {
+	s.MinValues = n
+	return s
+}
+
+// SetMaxValues of the selectMenuCreator
+func (s *selectMenuCreator) SetMaxValues(n int) SelectMenuBuilder {
+	s.MaxValues = n
+	return s
+}
+
+// AddOption to the selectMenuCreator
+func (s *selectMenuCreator) AddOption(o SelectOptionBuilder) SelectMenuBuilder {
+	s.Options = append(s.Options, o)
+	return s
+}
+
+// AddChannelType to the selectMenuCreator
+func (s *selectMenuCreator) AddChannelType(t types.Channel) SelectMenuBuilder {
+	s.ChannelTypes = append(s.ChannelTypes, t)
+	return s
+}
+
+// AddDefaultValue to the selectMenuCreator
+func (s *selectMenuCreator) AddDefaultValue(id string, t types.SelectMenuDefaultValue) SelectMenuBuilder {
+	s.DefaultValues = append(s.DefaultValues, component.SelectMenuDefaultValue{ID: id, Type: t})
+	return s
+}
+
+// IsDisabled informs that the selectMenuCreator is disabled
+func (s *selectMenuCreator) IsDisabled() SelectMenuBuilder {
+	s.Disabled = true
+	return s
+}
+
+// SetHandler called when values of the selectMenuCreator are selected
+func (s *selectMenuCreator) SetHandler(handler ComponentHandler) SelectMenuBuilder {
+	s.Handler = handler
+	return s
+}
+
+// toComponent turns selectMenuCreator into a component.SelectMenu
+func (s *selectMenuCreator) toComponent() (component.Component, error) {
+	if !validCustomID(s.CustomID) {
+		return nil, ErrInvalidCustomID
+	}
+	if s.MinValues < 0 || s.MaxValues < 1 || s.MinValues > s.MaxValues || s.MaxValues > MaxSelectOptions {
+		return nil, ErrInvalidMinMaxValues
+	}
+	menu := &component.SelectMenu{
+		MenuType:      s.MenuType,
+		CustomID:      s.CustomID,
+		Placeholder:   s.Placeholder,
+		MinValues:     &s.MinValues,
+		MaxValues:     s.MaxValues,
+		DefaultValues: s.DefaultValues,
+		ChannelTypes:  s.ChannelTypes,
+		Disabled:      s.Disabled,
+	}
+	if s.MenuType != types.SelectMenuString {
+		return menu, nil
+	}
+	if len(s.Options) == 0 {
+		return nil, ErrNoOptions
+	}
+	if len(s.Options) > MaxSelectOptions {
+		return nil, ErrTooManyOptions
+	}
+	if s.MaxValues > len(s.Options) {
+		return nil, ErrInvalidMinMaxValues
+	}
+	menu.Options = make([]component.SelectMenuOption, len(s.Options))
+	for i, o := range s.Options {
+		menu.Options[i] = o.toDiscordOption()
+	}
+	return menu, nil
+}
+
+func (s *selectMenuCreator) bind(r *Router) []string {
+	if s.Handler == nil {
+		return nil
+	}
+	r.HandleComponent(s.CustomID, s.Handler)
+	return []string{s.CustomID}
+}
+
+func (s *selectMenuCreator) width() int {
+	return MaxActionRowWidth
+}
+
+// SetDescription of the selectOptionCreator
+func (o *selectOptionCreator) SetDescription(d string) SelectOptionBuilder {
+	o.Description = d
+	return o
+}
+
+// SetEmoji of the selectOptionCreator
+func (o *selectOptionCreator) SetEmoji(e *component.Emoji) SelectOptionBuilder {
+	o.Emoji = e
+	return o
+}
+
+// IsDefault informs that the selectOptionCreator is selected by default
+func (o *selectOptionCreator) IsDefault() SelectOptionBuilder {
+	o.Default = true
+	return o
+}
+
+// toDiscordOption turns selectOptionCreator into a component.SelectMenuOption
+func (o *selectOptionCreator) toDiscordOption() component.SelectMenuOption {
+	return component.SelectMenuOption{
+		Label:       o.Label,
+		Value:       o.Value,
+		Description: o.Description,
+		Emoji:       o.Emoji,
+		Default:     o.Default,
+	}
+}
+
+func (t *textInputCreator) GetCustomID() string {
+	return t.CustomID
+}
+
+// SetPlaceholder of the textInputCreator
+func (t *textInputCreator) SetPlaceholder(p string) TextInputBuilder {
+	t.Placeholder = p
+	return t
+}
+
+// SetValue of the textInputCreator
+func (t *textInputCreator) SetValue(v string) TextInputBuilder {
+	t.Value = v
+	return t
+}
+
+// IsRequired informs that the textInputCreator is required
+func (t *textInputCreator) IsRequired() TextInputBuilder {
+	t.Required = true
+	return t
+}
+
+// SetLength of the textInputCreator
+func (t *textInputCreator) SetLength(min, max int) TextInputBuilder {
+	t.MinLength = min
+	t.MaxLength = max
+	return t
+}
+
+// toComponent turns textInputCreator into a component.TextInput
+func (t *textInputCreator) toComponent() (component.Component, error) {
+	if !validCustomID(t.CustomID) {
+		return nil, ErrInvalidCustomID
+	}
+	return &component.TextInput{
+		CustomID:    t.CustomID,
+		Label:       t.Label,
+		Style:       t.Style,
+		Placeholder: t.Placeholder,
+		Value:       t.Value,
+		Required:    t.Required,
+		MinLength:   t.MinLength,
+		MaxLength:   t.MaxLength,
+	}, nil
+}
+
+func (t *textInputCreator) bind(_ *Router) []string { return nil }
+
+func (t *textInputCreator) width() int {
+	return MaxActionRowWidth
+}
+
+func (t *textDisplayCreator) IsLayout() bool {
+	return true
+}
+
+// Component turns textDisplayCreator into a component.TextDisplay
+func (t *textDisplayCreator) Component() (component.Message, error) {
+	return &component.TextDisplay{Content: t.Content}, nil
+}
+
+func (t *textDisplayCreator) bind(_ *Router) []string { return nil }
+
+func (t *textDisplayCreator) size() int {
+	return 1
+}
+
+// AddText to the sectionCreator
+func (s *sectionCreator) AddText(content string) SectionBuilder {
+	s.Texts = append(s.Texts, content)
+	return s
+}
+
+// SetAccessory of the sectionCreator
+func (s *sectionCreator) SetAccessory(a AccessoryBuilder) SectionBuilder {
+	s.Accessory = a
+	return s
+}
+
+func (s *sectionCreator) IsLayout() bool {
+	return true
+}
+
+// Component turns sectionCreator into a component.Section
+func (s *sectionCreator) Component() (component.Message, error) {
+	if len(s.Texts) == 0 || len(s.Texts) > MaxSectionTexts {
+		return nil, ErrInvalidSectionTexts
+	}
+	if s.Accessory == nil {
+		return nil, ErrMissingAccessory
+	}
+	acc, err := s.Accessory.toAccessory()
+	if err != nil {
+		return nil, err
+	}
+	texts := make([]component.Component, len(s.Texts))
+	for i, t := range s.Texts {
+		texts[i] = &component.TextDisplay{Content: t}
+	}
+	return &component.Section{
+		Components: texts,
+		Accessory:  acc,
+	}, nil
+}
+
+func (s *sectionCreator) bind(r *Router) []string {
+	if s.Accessory == nil {
+		return nil
+	}
+	return s.Accessory.bind(r)
+}
+
+func (s *sectionCreator) size() int {
+	return len(s.Texts) + 2
+}
+
+// SetDescription of the thumbnailCreator
+func (t *thumbnailCreator) SetDescription(d string) ThumbnailBuilder {
+	t.Description = d
+	return t
+}
+
+// IsSpoiler informs that the thumbnailCreator is a spoiler
+func (t *thumbnailCreator) IsSpoiler() ThumbnailBuilder {
+	t.Spoiler = true
+	return t
+}
+
+// toAccessory turns thumbnailCreator into a component.Thumbnail
+func (t *thumbnailCreator) toAccessory() (component.Component, error) {
+	if t.URL == "" {
+		return nil, ErrInvalidURL
+	}
+	thumb := &component.Thumbnail{
+		Media:   component.UnfurledMediaItem{URL: t.URL},
+		Spoiler: t.Spoiler,
+	}
+	if t.Description != "" {
+		thumb.Description = &t.Description
+	}
+	return thumb, nil
+}
+
+func (t *thumbnailCreator) bind(_ *Router) []string { return nil }
+
+// AddItem to the mediaGalleryCreator
+func (m *mediaGalleryCreator) AddItem(url string, description string, spoiler bool) MediaGalleryBuilder {
+	item := component.MediaGalleryItem{
+		Media:   component.UnfurledMediaItem{URL: url},
+		Spoiler: spoiler,
+	}
+	if description != "" {
+		item.Description = &description
+	}
+	m.Items = append(m.Items, item)
+	return m
+}
+
+func (m *mediaGalleryCreator) IsLayout() bool {
+	return true
+}
+
+// Component turns mediaGalleryCreator into a component.MediaGallery
+func (m *mediaGalleryCreator) Component() (component.Message, error) {
+	if len(m.Items) == 0 || len(m.Items) > MaxGalleryItems {
+		return nil, ErrInvalidGalleryItems
+	}
+	for _, it := range m.Items {
+		if it.Media.URL == "" {
+			return nil, ErrInvalidURL
+		}
+	}
+	return &component.MediaGallery{Items: m.Items}, nil
+}
+
+func (m *mediaGalleryCreator) bind(_ *Router) []string { return nil }
+
+func (m *mediaGalleryCreator) size() int {
+	return 1
+}
+
+// IsSpoiler informs that the fileComponentCreator is a spoiler
+func (f *fileComponentCreator) IsSpoiler() FileComponentBuilder {
+	f.Spoiler = true
+	return f
+}
+
+func (f *fileComponentCreator) IsLayout() bool {
+	return true
+}
+
+// Component turns fileComponentCreator into a component.File
+func (f *fileComponentCreator) Component() (component.Message, error) {
+	if f.URL == "" {
+		return nil, ErrInvalidURL
+	}
+	return &component.File{
+		File:    component.UnfurledMediaItem{URL: f.URL},
+		Spoiler: f.Spoiler,
+	}, nil
+}
+
+func (f *fileComponentCreator) bind(_ *Router) []string { return nil }
+
+func (f *fileComponentCreator) size() int {
+	return 1
+}
+
+// WithoutDivider hides the line of the separatorCreator
+func (s *separatorCreator) WithoutDivider() SeparatorBuilder {
+	s.Divider = false
+	return s
+}
+
+// SetSpacing of the separatorCreator
+func (s *separatorCreator) SetSpacing(sp types.SeparatorSpacing) SeparatorBuilder {
+	s.Spacing = &sp
+	return s
+}
+
+func (s *separatorCreator) IsLayout() bool {
+	return true
+}
+
+// Component turns separatorCreator into a component.Separator
+func (s *separatorCreator) Component() (component.Message, error) {
+	return &component.Separator{
+		Divider: &s.Divider,
+		Spacing: s.Spacing,
+	}, nil
+}
+
+func (s *separatorCreator) bind(_ *Router) []string { return nil }
+
+func (s *separatorCreator) size() int {
+	return 1
+}
+
+// AddComponent to the containerCreator
+func (c *containerCreator) AddComponent(cmp ComponentBuilder) ContainerBuilder {
+	c.Components = append(c.Components, cmp)
+	return c
+}
+
+// SetAccentColor of the containerCreator
+func (c *containerCreator) SetAccentColor(color int) ContainerBuilder {
+	c.AccentColor = &color
+	return c
+}
+
+// IsSpoiler informs that the containerCreator is a spoiler
+func (c *containerCreator) IsSpoiler() ContainerBuilder {
+	c.Spoiler = true
+	return c
+}
+
+func (c *containerCreator) IsLayout() bool {
+	return true
+}
+
+// Component turns containerCreator into a component.Container
+func (c *containerCreator) Component() (component.Message, error) {
+	if len(c.Components) == 0 {
+		return nil, ErrEmptyContainer
+	}
+	cmps := make([]component.Message, len(c.Components))
+	for i, cb := range c.Components {
+		cmp, err := cb.Component()
+		if err != nil {
+			return nil, err
+		}
+		cmps[i] = cmp
+	}
+	return &component.Container{
+		AccentColor: c.AccentColor,
+		Spoiler:     c.Spoiler,
+		Components:  cmps,
+	}, nil
+}
+
+func (c *containerCreator) bind(r *Router) []string {
+	var ids []string
+	for _, cb := range c.Components {
+		ids = append(ids, cb.bind(r)...)
+	}
+	return ids
+}
+
+func (c *containerCreator) size() int {
+	s := 1
+	for _, cb := range c.Components {
+		s += cb.size()
+	}
+	return s
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import "time"
+
+type CooldownScope int
+
+const (
+	CooldownUser    CooldownScope = 0 // CooldownUser is shared by every use of a user
+	CooldownGuild   CooldownScope = 1 // CooldownGuild is shared by every user of a guild
+	CooldownChannel CooldownScope = 2 // CooldownChannel is shared by every user of a channel
+	CooldownGlobal  CooldownScope = 3 // CooldownGlobal is shared by everyone
+)
+
+type CooldownStrategy int
+
+const (
+	// FixedWindow allows Cooldown.Limit uses per Cooldown.Period
+	FixedWindow CooldownStrategy = 0
+	// TokenBucket allows bursts of Cooldown.Limit uses, and a new use is available every Cooldown.Period / Cooldown.Limit
+	TokenBucket CooldownStrategy = 1
+)
+
+// Cooldown limits the number of uses of a command
+type Cooldown struct {
+	Scope    CooldownScope
+	Strategy CooldownStrategy
+	Limit    int           // Limit is the number of uses allowed per Period
+	Period   time.Duration // Period of the Cooldown
+}
+
+// NewCooldown creates a new Cooldown allowing limit uses per period with the FixedWindow strategy
+func NewCooldown(scope CooldownScope, limit int, period time.Duration) *Cooldown {
+	return &Cooldown{
+		Scope:    scope,
+		Strategy: FixedWindow,
+		Limit:    limit,
+		Period:   period,
+	}
+}
+
+// WithTokenBucket uses the TokenBucket strategy
+func (c *Cooldown) WithTokenBucket() *Cooldown {
+	c.Strategy = TokenBucket
+	return c
+}
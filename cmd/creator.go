@@ -23,15 +23,23 @@ type commandCreator struct {
 	Options          []CommandOptionBuilder
 	Subs             []CommandBuilder
 	Handler          CommandHandler // Handler called
+	Cooldown         *Cooldown
+	Guards           []Guard
+	Scoped           bool // Scoped is true if the command is only registered in guilds which enabled it
 }
 
 // commandOptionCreator represents a generic option of commandCreator
 type commandOptionCreator struct {
-	Type        types.CommandOption
-	Name        string
-	Description string
-	Required    bool
-	Choices     []CommandChoiceBuilder
+	Type         types.CommandOption
+	Name         string
+	Description  string
+	Required     bool
+	Choices      []CommandChoiceBuilder
+	MinValue     *float64
+	MaxValue     float64
+	MinLength    *int
+	MaxLength    int
+	Autocomplete bool
 }
 
 // commandChoiceCreator represents a generic choice of commandOptionCreator
@@ -56,6 +64,18 @@ func (c *commandCreator) GetSubs() []CommandBuilder {
 	return c.Subs
 }
 
+func (c *commandCreator) GetCooldown() *Cooldown {
+	return c.Cooldown
+}
+
+func (c *commandCreator) GetGuards() []Guard {
+	return c.Guards
+}
+
+func (c *commandCreator) GuildScoped() bool {
+	return c.Scoped
+}
+
 func (c *commandCreator) setSub(b bool) {
 	c.IsSub = b
 }
@@ -119,6 +139,24 @@ func (c *commandCreator) SetPermission(p *int64) CommandBuilder {
 	return c
 }
 
+// SetCooldown of the commandCreator
+func (c *commandCreator) SetCooldown(cd *Cooldown) CommandBuilder {
+	c.Cooldown = cd
+	return c
+}
+
+// IsGuildScoped informs that the commandCreator is only registered in guilds which enabled it
+func (c *commandCreator) IsGuildScoped() CommandBuilder {
+	c.Scoped = true
+	return c
+}
+
+// AddGuard to the commandCreator
+func (c *commandCreator) AddGuard(g ...Guard) CommandBuilder {
+	c.Guards = append(c.Guards, g...)
+	return c
+}
+
 // Is returns true if the commandCreator is approximately the same as *interaction.Command
 func (c *commandCreator) Is(cmd *interaction.Command) bool {
 	return cmd.DefaultMemberPermissions == c.Permission &&
@@ -190,6 +228,12 @@ func (o *commandOptionCreator) IsRequired() CommandOptionBuilder {
 	return o
 }
 
+// NotRequired informs that the commandOptionCreator is optional
+func (o *commandOptionCreator) NotRequired() CommandOptionBuilder {
+	o.Required = false
+	return o
+}
+
 // AddChoice to the commandOptionCreator
 func (o *commandOptionCreator) AddChoice(c CommandChoiceBuilder) CommandOptionBuilder {
 	o.Required = true
@@ -197,6 +241,36 @@ func (o *commandOptionCreator) AddChoice(c CommandChoiceBuilder) CommandOptionBu
 	return o
 }
 
+// SetMinValue of the commandOptionCreator
+func (o *commandOptionCreator) SetMinValue(v float64) CommandOptionBuilder {
+	o.MinValue = &v
+	return o
+}
+
+// SetMaxValue of the commandOptionCreator
+func (o *commandOptionCreator) SetMaxValue(v float64) CommandOptionBuilder {
+	o.MaxValue = v
+	return o
+}
+
+// SetMinLength of the commandOptionCreator
+func (o *commandOptionCreator) SetMinLength(l int) CommandOptionBuilder {
+	o.MinLength = &l
+	return o
+}
+
+// SetMaxLength of the commandOptionCreator
+func (o *commandOptionCreator) SetMaxLength(l int) CommandOptionBuilder {
+	o.MaxLength = l
+	return o
+}
+
+// IsAutocomplete informs that the choices of the commandOptionCreator are sent by an AutocompleteHandler
+func (o *commandOptionCreator) IsAutocomplete() CommandOptionBuilder {
+	o.Autocomplete = true
+	return o
+}
+
 // toDiscordOption turns commandOptionCreator into a interaction.CommandOption
 func (o *commandOptionCreator) toDiscordOption() *interaction.CommandOption {
 	var choices []*interaction.CommandOptionChoice
@@ -204,11 +278,16 @@ func (o *commandOptionCreator) toDiscordOption() *interaction.CommandOption {
 		choices = append(choices, c.toDiscordChoice())
 	}
 	return &interaction.CommandOption{
-		Type:        o.Type,
-		Name:        o.Name,
-		Description: o.Description,
-		Required:    o.Required,
-		Choices:     choices,
+		Type:         o.Type,
+		Name:         o.Name,
+		Description:  o.Description,
+		Required:     o.Required,
+		Choices:      choices,
+		MinValue:     o.MinValue,
+		MaxValue:     o.MaxValue,
+		MinLength:    o.MinLength,
+		MaxLength:    o.MaxLength,
+		Autocomplete: o.Autocomplete,
 	}
 }
 
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nyttikord/gokord/channel"
+)
+
+const (
+	MaxEmbeds                 = 10   // MaxEmbeds in a message
+	MaxEmbedsLength           = 6000 // MaxEmbedsLength is the total number of characters of every embed in a message
+	MaxEmbedFields            = 25
+	MaxEmbedTitleLength       = 256
+	MaxEmbedDescriptionLength = 4096
+	MaxEmbedFieldNameLength   = 256
+	MaxEmbedFieldValueLength  = 1024
+	MaxEmbedFooterLength      = 2048
+	MaxEmbedAuthorLength      = 256
+)
+
+var (
+	ErrTooManyEmbeds      = errors.New("too many embeds in the message")
+	ErrEmbedsTooLong      = errors.New("embeds are too long (6000 characters for every embed of the message)")
+	ErrTooManyEmbedFields = errors.New("too many fields in embed")
+	ErrEmbedFieldTooLong  = errors.New("embed field name or value is too long")
+	ErrEmbedFieldEmpty    = errors.New("embed field name or value is empty")
+	ErrEmbedTitleTooLong  = errors.New("embed title is too long")
+	ErrEmbedDescTooLong   = errors.New("embed description is too long")
+	ErrEmbedFooterTooLong = errors.New("embed footer is too long")
+	ErrEmbedAuthorTooLong = errors.New("embed author name is too long")
+)
+
+type EmbedBuilder interface {
+	// SetTitle of the EmbedBuilder
+	SetTitle(t string) EmbedBuilder
+	// SetDescription of the EmbedBuilder
+	SetDescription(d string) EmbedBuilder
+	// SetURL opened when clicking on the title
+	SetURL(url string) EmbedBuilder
+	// SetColor of the EmbedBuilder.
+	// If it is not set, the color of the Branding is used
+	SetColor(c int) EmbedBuilder
+	// SetImage of the EmbedBuilder
+	SetImage(url string) EmbedBuilder
+	// SetThumbnail of the EmbedBuilder
+	SetThumbnail(url string) EmbedBuilder
+	// SetFooter of the EmbedBuilder, iconURL may be empty.
+	// If it is not set, the footer of the Branding is used
+	SetFooter(text string, iconURL string) EmbedBuilder
+	// SetAuthor of the EmbedBuilder, url and iconURL may be empty.
+	// If it is not set, the author of the Branding is used
+	SetAuthor(name string, url string, iconURL string) EmbedBuilder
+	// SetTimestamp of the EmbedBuilder
+	SetTimestamp(t time.Time) EmbedBuilder
+	// AddField to the EmbedBuilder (up to 25)
+	AddField(name string, value string, inline bool) EmbedBuilder
+	// WithoutBranding disables the Branding for this EmbedBuilder
+	WithoutBranding() EmbedBuilder
+	// IsBranded returns true if the Branding must be applied
+	IsBranded() bool
+	// Embed returns the embed understandable by Discord
+	Embed() (*channel.MessageEmbed, error)
+}
+
+// embedCreator represents a generic embed
+type embedCreator struct {
+	embed    *channel.MessageEmbed
+	branding bool
+}
+
+// NewEmbed creates a new EmbedBuilder
+func NewEmbed() EmbedBuilder {
+	return &embedCreator{
+		embed:    &channel.MessageEmbed{},
+		branding: true,
+	}
+}
+
+// SetTitle of the embedCreator
+func (e *embedCreator) SetTitle(t string) EmbedBuilder {
+	e.embed.Title = t
+	return e
+}
+
+// SetDescription of the embedCreator
+func (e *embedCreator) SetDescription(d string) EmbedBuilder {
+	e.embed.Description = d
+	return e
+}
+
+// SetURL of the embedCreator
+func (e *embedCreator) SetURL(url string) EmbedBuilder {
+	e.embed.URL = url
+	return e
+}
+
+// SetColor of the embedCreator
+func (e *embedCreator) SetColor(c int) EmbedBuilder {
+	e.embed.Color = c
+	return e
+}
+
+// SetImage of the embedCreator
+func (e *embedCreator) SetImage(url string) EmbedBuilder {
+	e.embed.Image = &channel.MessageEmbedImage{URL: url}
+	return e
+}
+
+// SetThumbnail of the embedCreator
+func (e *embedCreator) SetThumbnail(url string) EmbedBuilder {
+	e.embed.Thumbnail = &channel.MessageEmbedThumbnail{URL: url}
+	return e
+}
+
+// SetFooter of the embedCreator
+func (e *embedCreator) SetFooter(text string, iconURL string) EmbedBuilder {
+	e.embed.Footer = &channel.MessageEmbedFooter{Text: text, IconURL: iconURL}
+	return e
+}
+
+// SetAuthor of the embedCreator
+func (e *embedCreator) SetAuthor(name string, url string, iconURL string) EmbedBuilder {
+	e.embed.Author = &channel.MessageEmbedAuthor{Name: name, URL: url, IconURL: iconURL}
+	return e
+}
+
+// SetTimestamp of the embedCreator
+func (e *embedCreator) SetTimestamp(t time.Time) EmbedBuilder {
+	e.embed.Timestamp = t.Format(time.RFC3339)
+	return e
+}
+
+// AddField to the embedCreator
+func (e *embedCreator) AddField(name string, value string, inline bool) EmbedBuilder {
+	e.embed.Fields = append(e.embed.Fields, &channel.MessageEmbedField{Name: name, Value: value, Inline: inline})
+	return e
+}
+
+// WithoutBranding disables the Branding for this embedCreator
+func (e *embedCreator) WithoutBranding() EmbedBuilder {
+	e.branding = false
+	return e
+}
+
+func (e *embedCreator) IsBranded() bool {
+	return e.branding
+}
+
+// Embed validates the embedCreator and turns it into a channel.MessageEmbed
+func (e *embedCreator) Embed() (*channel.MessageEmbed, error) {
+	if err := ValidateEmbed(e.embed); err != nil {
+		return nil, err
+	}
+	return e.embed, nil
+}
+
+// ValidateEmbed checks if the embed respects the limits of Discord
+func ValidateEmbed(e *channel.MessageEmbed) error {
+	if utf8.RuneCountInString(e.Title) > MaxEmbedTitleLength {
+		return ErrEmbedTitleTooLong
+	}
+	if utf8.RuneCountInString(e.Description) > MaxEmbedDescriptionLength {
+		return ErrEmbedDescTooLong
+	}
+	if len(e.Fields) > MaxEmbedFields {
+		return ErrTooManyEmbedFields
+	}
+	for _, f := range e.Fields {
+		if f.Name == "" || f.Value == "" {
+			return ErrEmbedFieldEmpty
+		}
+		if utf8.RuneCountInString(f.Name) > MaxEmbedFieldNameLength ||
+			utf8.RuneCountInString(f.Value) > MaxEmbedFieldValueLength {
+			return ErrEmbedFieldTooLong
+		}
+	}
+	if e.Footer != nil && utf8.RuneCountInString(e.Footer.Text) > MaxEmbedFooterLength {
+		return ErrEmbedFooterTooLong
+	}
+	if e.Author != nil && utf8.RuneCountInString(e.Author.Name) > MaxEmbedAuthorLength {
+		return ErrEmbedAuthorTooLong
+	}
+	if embedLength(e) > MaxEmbedsLength {
+		return ErrEmbedsTooLong
+	}
+	return nil
+}
+
+// validateEmbeds checks the limits of every embed of a message
+func validateEmbeds(embeds []*channel.MessageEmbed) error {
+	if len(embeds) > MaxEmbeds {
+		return ErrTooManyEmbeds
+	}
+	l := 0
+	for _, e := range embeds {
+		if err := ValidateEmbed(e); err != nil {
+			return err
+		}
+		l += embedLength(e)
+	}
+	if l > MaxEmbedsLength {
+		return ErrEmbedsTooLong
+	}
+	return nil
+}
+
+// embedLength returns the number of characters counted by Discord
+func embedLength(e *channel.MessageEmbed) int {
+	l := utf8.RuneCountInString(e.Title) + utf8.RuneCountInString(e.Description)
+	for _, f := range e.Fields {
+		l += utf8.RuneCountInString(f.Name) + utf8.RuneCountInString(f.Value)
+	}
+	if e.Footer != nil {
+		l += utf8.RuneCountInString(e.Footer.Text)
+	}
+	if e.Author != nil {
+		l += utf8.RuneCountInString(e.Author.Name)
+	}
+	return l
+}
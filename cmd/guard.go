@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/discord"
+	"github.com/nyttikord/gokord/event"
+)
+
+type Denial int
+
+const (
+	DeniedInternal       Denial = 0 // DeniedInternal is used when a Guard returns an error that is not a GuardError
+	DeniedPermissions    Denial = 1
+	DeniedRoles          Denial = 2
+	DeniedOwnerOnly      Denial = 3
+	DeniedGuildOnly      Denial = 4
+	DeniedDMOnly         Denial = 5
+	DeniedGuild          Denial = 6
+	DeniedBotPermissions Denial = 7
+	DeniedModule         Denial = 8 // DeniedModule is used when the module of the command is disabled in the guild
+)
+
+// DenialMessages are sent when a Guard denies the use of a command, indexed by the locale of the user.
+//
+// The message linked with "" is used if the locale is not present.
+var DenialMessages = map[Denial]map[string]string{
+	DeniedInternal: {
+		"":   "Internal error, please report it",
+		"fr": "Erreur interne, merci de la signaler",
+	},
+	DeniedPermissions: {
+		"":   "You do not have the permissions required to use this command.",
+		"fr": "Tu n'as pas les permissions requises pour utiliser cette commande.",
+	},
+	DeniedRoles: {
+		"":   "You do not have the roles required to use this command.",
+		"fr": "Tu n'as pas les rôles requis pour utiliser cette commande.",
+	},
+	DeniedOwnerOnly: {
+		"":   "Only the owners of the bot can use this command.",
+		"fr": "Seuls les propriétaires du bot peuvent utiliser cette commande.",
+	},
+	DeniedGuildOnly: {
+		"":   "This command can only be used in a server.",
+		"fr": "Cette commande ne peut être utilisée que dans un serveur.",
+	},
+	DeniedDMOnly: {
+		"":   "This command can only be used in direct messages.",
+		"fr": "Cette commande ne peut être utilisée qu'en messages privés.",
+	},
+	DeniedGuild: {
+		"":   "This command is not available in this server.",
+		"fr": "Cette commande n'est pas disponible dans ce serveur.",
+	},
+	DeniedBotPermissions: {
+		"":   "I do not have the permissions required to run this command in this channel.",
+		"fr": "Je n'ai pas les permissions requises pour exécuter cette commande dans ce salon.",
+	},
+	DeniedModule: {
+		"":   "This feature is disabled in this server.",
+		"fr": "Cette fonctionnalité est désactivée dans ce serveur.",
+	},
+}
+
+// DenialMessage returns the message of DenialMessages linked with the Denial and the locale
+func DenialMessage(d Denial, locale string) string {
+	return LocalizedMessage(DenialMessages[d], locale)
+}
+
+// LocalizedMessage returns the message of msgs linked with the locale.
+// The language of the locale is used if the locale is not present, and the message linked with "" if both are absent.
+func LocalizedMessage(msgs map[string]string, locale string) string {
+	if msg, ok := msgs[locale]; ok {
+		return msg
+	}
+	// "en-US" -> "en"
+	if lang, _, ok := strings.Cut(locale, "-"); ok {
+		if msg, ok := msgs[lang]; ok {
+			return msg
+		}
+	}
+	return msgs[""]
+}
+
+// GuardContext is given to a Guard
+type GuardContext struct {
+	// Context of the handler
+	Context     context.Context
+	Session     bot.Session
+	Interaction *event.InteractionCreate
+	// IsOwner returns true if the user is an owner of the bot
+	IsOwner func(userID string) bool
+}
+
+// Guard is checked before calling the handler of a command.
+// It returns a GuardError if the command cannot be used.
+type Guard func(ctx *GuardContext) error
+
+// GuardError is returned by a Guard denying the use of a command
+type GuardError struct {
+	Denial  Denial
+	Message string // Message sent to the user, DenialMessages[Denial] is used if empty
+}
+
+func (e *GuardError) Error() string {
+	return e.GetMessage()
+}
+
+// GetMessage returns the message sent to the user (in the default locale, see Localized)
+func (e *GuardError) GetMessage() string {
+	return e.Localized("")
+}
+
+// Localized returns the message sent to the user with the given locale
+func (e *GuardError) Localized(locale string) string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return DenialMessage(e.Denial, locale)
+}
+
+// Deny returns a GuardError with the default message of the Denial
+func Deny(d Denial) error {
+	return &GuardError{Denial: d}
+}
+
+// RequirePermissions denies users without all the given permissions (in a guild only)
+func RequirePermissions(p int64) Guard {
+	return func(ctx *GuardContext) error {
+		m := ctx.Interaction.Member
+		if m == nil {
+			return Deny(DeniedGuildOnly)
+		}
+		if m.Permissions&discord.PermissionAdministrator == 0 && m.Permissions&p != p {
+			return Deny(DeniedPermissions)
+		}
+		return nil
+	}
+}
+
+// RequireAnyRole denies users without at least one of the given roles (in a guild only)
+func RequireAnyRole(roles ...string) Guard {
+	return func(ctx *GuardContext) error {
+		m := ctx.Interaction.Member
+		if m == nil {
+			return Deny(DeniedGuildOnly)
+		}
+		if !slices.ContainsFunc(roles, func(r string) bool { return slices.Contains(m.Roles, r) }) {
+			return Deny(DeniedRoles)
+		}
+		return nil
+	}
+}
+
+// RequireAllRoles denies users without all the given roles (in a guild only)
+func RequireAllRoles(roles ...string) Guard {
+	return func(ctx *GuardContext) error {
+		m := ctx.Interaction.Member
+		if m == nil {
+			return Deny(DeniedGuildOnly)
+		}
+		for _, r := range roles {
+			if !slices.Contains(m.Roles, r) {
+				return Deny(DeniedRoles)
+			}
+		}
+		return nil
+	}
+}
+
+// OwnerOnly denies users who are not owners of the bot
+func OwnerOnly() Guard {
+	return func(ctx *GuardContext) error {
+		if ctx.IsOwner == nil || !ctx.IsOwner(InteractionUserID(ctx.Interaction)) {
+			return Deny(DeniedOwnerOnly)
+		}
+		return nil
+	}
+}
+
+// GuildOnly denies uses outside guilds
+func GuildOnly() Guard {
+	return func(ctx *GuardContext) error {
+		if ctx.Interaction.GuildID == "" {
+			return Deny(DeniedGuildOnly)
+		}
+		return nil
+	}
+}
+
+// DMOnly denies uses in guilds
+func DMOnly() Guard {
+	return func(ctx *GuardContext) error {
+		if ctx.Interaction.GuildID != "" {
+			return Deny(DeniedDMOnly)
+		}
+		return nil
+	}
+}
+
+// AllowGuilds denies uses outside the given guilds
+func AllowGuilds(guilds ...string) Guard {
+	return func(ctx *GuardContext) error {
+		if !slices.Contains(guilds, ctx.Interaction.GuildID) {
+			return Deny(DeniedGuild)
+		}
+		return nil
+	}
+}
+
+// BotPermissions denies uses in channels where the bot does not have all the given permissions
+func BotPermissions(p int64) Guard {
+	return func(ctx *GuardContext) error {
+		perms := ctx.Interaction.AppPermissions
+		if perms&discord.PermissionAdministrator == 0 && perms&p != p {
+			return Deny(DeniedBotPermissions)
+		}
+		return nil
+	}
+}
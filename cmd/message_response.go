@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/channel"
+	"github.com/nyttikord/gokord/event"
+)
+
+var ErrModalInMessage = errors.New("modal cannot be sent in reply to a message")
+
+// NewMessageResponseBuilder creates a new ResponseBuilder replying to the message instead of responding to an
+// interaction.
+// It is used by prefix commands: i is the interaction generated from the message.
+//
+// Ephemeral responses are sent as normal messages, deferred responses send nothing, and editing the response edits the
+// reply.
+func NewMessageResponseBuilder(s bot.Session, i *event.InteractionCreate, m *channel.Message) *ResponseBuilder {
+	res := NewResponseBuilder(s, i)
+	res.message = m
+	return res
+}
+
+// Message returns the message replied to by the ResponseBuilder (nil if it responds to an interaction)
+func (res *ResponseBuilder) Message() *channel.Message {
+	return res.message
+}
+
+// Reply returns the reply sent to the message (nil if nothing was sent or if it responds to an interaction)
+func (res *ResponseBuilder) Reply() *channel.Message {
+	return res.reply
+}
+
+// sendReply sends the response in reply to the message
+func (res *ResponseBuilder) sendReply() error {
+	if res.modal {
+		return ErrModalInMessage
+	}
+	if res.deferred {
+		// nothing to send, the next response creates the reply
+		res.IsEdit()
+		return nil
+	}
+	cmps, err := res.messageComponents()
+	if err != nil {
+		return err
+	}
+	if (res.edit || res.update) && res.reply != nil {
+		if res.poll != nil {
+			return ErrPollNotEditable
+		}
+		edit := &channel.MessageEdit{
+			ID:              res.reply.ID,
+			Channel:         res.reply.ChannelID,
+			Content:         &res.content,
+			Components:      &cmps,
+			Embeds:          &res.embeds,
+			Files:           res.files,
+			AllowedMentions: res.mentions,
+			Flags:           res.flags,
+		}
+		_, err = res.session.ChannelAPI().MessageEditComplex(edit)
+		if err != nil {
+			res.session.Logger().Debug("editing reply", "error", err, "edit", formatInteractionResponse(edit))
+		}
+		return err
+	}
+	params := &channel.MessageSend{
+		Content:         res.content,
+		Embeds:          res.embeds,
+		TTS:             res.tts,
+		Components:      cmps,
+		Files:           res.files,
+		AllowedMentions: res.mentions,
+		Reference:       res.message.Reference(),
+		Flags:           res.messageFlags() &^ channel.MessageFlagsEphemeral,
+		Poll:            res.poll,
+	}
+	m, err := res.session.ChannelAPI().MessageSendComplex(res.message.ChannelID, params)
+	if err != nil {
+		res.session.Logger().Debug("sending reply", "error", err, "message", formatInteractionResponse(params))
+		return err
+	}
+	// followups are new replies, the first one is edited
+	if res.reply == nil {
+		res.reply = m
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"github.com/nyttikord/gokord/component"
 	"github.com/nyttikord/gokord/event"
 	"github.com/nyttikord/gokord/interaction"
 )
@@ -24,3 +25,23 @@ func GenerateOptionMapForSubcommand(i *event.InteractionCreate) OptionMap {
 	}
 	return optionMap
 }
+
+// GenerateModalValues returns the value of each text input of the submitted modal, indexed by their custom ID
+func GenerateModalValues(data *interaction.ModalSubmitData) map[string]string {
+	values := make(map[string]string)
+	var walk func(cmps []component.Component)
+	walk = func(cmps []component.Component) {
+		for _, c := range cmps {
+			switch v := c.(type) {
+			case *component.ActionsRow:
+				walk(v.Components)
+			case *component.Label:
+				walk([]component.Component{v.Component})
+			case *component.TextInput:
+				values[v.CustomID] = v.Value
+			}
+		}
+	}
+	walk(data.Components)
+	return values
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/channel"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/interaction"
+)
+
+// DefaultPaginatorTimeout is the default duration of inactivity before disabling the buttons of a Paginator
+const DefaultPaginatorTimeout = 5 * time.Minute
+
+// interactionTokenLifetime is the duration during which the token of an interaction can be used to edit its response
+const interactionTokenLifetime = 15 * time.Minute
+
+var (
+	ErrNoPages         = errors.New("page source does not contain any page")
+	ErrPageOutOfBounds = errors.New("page out of bounds")
+)
+
+// PageSource provides pages of a Paginator
+type PageSource interface {
+	// Len returns the number of pages
+	Len() int
+	// Page returns the page n (starting at 0)
+	Page(n int) (*channel.MessageEmbed, error)
+}
+
+// staticPages is a PageSource with every page already in memory
+type staticPages []*channel.MessageEmbed
+
+// lazyPages is a PageSource fetching its pages only when they are displayed
+type lazyPages struct {
+	mu    sync.Mutex
+	len   int
+	fetch func(n int) (*channel.MessageEmbed, error)
+	cache map[int]*channel.MessageEmbed
+}
+
+// Paginator displays a PageSource with navigation buttons.
+// Only the user who invoked the command can navigate through pages.
+type Paginator struct {
+	mu        sync.Mutex
+	source    PageSource
+	timeout   time.Duration
+	forbidden string
+	current   int
+	timer     *time.Timer
+	//
+	userID    string
+	prevID    string
+	nextID    string
+	counterID string
+	resp      *ResponseBuilder
+	router    *Router
+}
+
+// StaticPages creates a PageSource from the given embeds
+func StaticPages(pages ...*channel.MessageEmbed) PageSource {
+	return staticPages(pages)
+}
+
+// LazyPages creates a PageSource with n pages fetched by fetch when needed.
+// Fetched pages are cached.
+func LazyPages(n int, fetch func(n int) (*channel.MessageEmbed, error)) PageSource {
+	return &lazyPages{
+		len:   n,
+		fetch: fetch,
+		cache: make(map[int]*channel.MessageEmbed),
+	}
+}
+
+func (p staticPages) Len() int {
+	return len(p)
+}
+
+func (p staticPages) Page(n int) (*channel.MessageEmbed, error) {
+	if n < 0 || n >= len(p) {
+		return nil, ErrPageOutOfBounds
+	}
+	return p[n], nil
+}
+
+func (p *lazyPages) Len() int {
+	return p.len
+}
+
+func (p *lazyPages) Page(n int) (*channel.MessageEmbed, error) {
+	if n < 0 || n >= p.len {
+		return nil, ErrPageOutOfBounds
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e, ok := p.cache[n]; ok {
+		return e, nil
+	}
+	e, err := p.fetch(n)
+	if err != nil {
+		return nil, err
+	}
+	p.cache[n] = e
+	return e, nil
+}
+
+// NewPaginator creates a new Paginator displaying the given PageSource
+func NewPaginator(source PageSource) *Paginator {
+	return &Paginator{
+		source:    source,
+		timeout:   DefaultPaginatorTimeout,
+		forbidden: "You cannot use this paginator.",
+	}
+}
+
+// SetTimeout sets the duration of inactivity before disabling the buttons.
+// It is capped to 15 minutes, the lifetime of the interaction used to disable them.
+func (p *Paginator) SetTimeout(d time.Duration) *Paginator {
+	p.timeout = d
+	return p
+}
+
+// SetForbiddenMessage sets the ephemeral message sent when another user tries to navigate
+func (p *Paginator) SetForbiddenMessage(msg string) *Paginator {
+	p.forbidden = msg
+	return p
+}
+
+// Send the first page as the response.
+// The given ResponseBuilder must not have been sent yet, or it must be in edit mode (after IsDeferred).
+func (p *Paginator) Send(resp *ResponseBuilder) error {
+	if p.source.Len() == 0 {
+		return ErrNoPages
+	}
+	if resp.router == nil {
+		return ErrRouterNotSet
+	}
+	p.resp = resp
+	p.router = resp.router
+	p.userID = InteractionUserID(resp.interaction)
+	p.prevID = fmt.Sprintf("gokord:pages:%s:prev", resp.interaction.ID)
+	p.nextID = fmt.Sprintf("gokord:pages:%s:next", resp.interaction.ID)
+	p.counterID = fmt.Sprintf("gokord:pages:%s:counter", resp.interaction.ID)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.render(resp, false); err != nil {
+		return err
+	}
+	if err := resp.Send(); err != nil {
+		p.unbind()
+		return err
+	}
+	p.timer = time.AfterFunc(p.expiration(), p.expire)
+	return nil
+}
+
+// render the current page in the ResponseBuilder
+func (p *Paginator) render(resp *ResponseBuilder, disabled bool) error {
+	page, err := p.source.Page(p.current)
+	if err != nil {
+		return err
+	}
+	prev := NewButton(p.prevID, "◀", types.ButtonStyleSecondary)
+	counter := NewButton(p.counterID, fmt.Sprintf("%d/%d", p.current+1, p.source.Len()), types.ButtonStyleSecondary).
+		IsDisabled()
+	next := NewButton(p.nextID, "▶", types.ButtonStyleSecondary)
+	if !disabled {
+		prev.SetHandler(p.previous)
+		next.SetHandler(p.next)
+	}
+	if disabled || p.current == 0 {
+		prev.IsDisabled()
+	}
+	if disabled || p.current == p.source.Len()-1 {
+		next.IsDisabled()
+	}
+	resp.embeds = nil
+	resp.AddEmbed(page).AddComponent(NewActionRow().AddComponent(prev).AddComponent(counter).AddComponent(next))
+	return nil
+}
+
+func (p *Paginator) previous(s bot.Session, i *event.InteractionCreate, _ *interaction.MessageComponentData, resp *ResponseBuilder) {
+	p.move(s, i, resp, -1)
+}
+
+func (p *Paginator) next(s bot.Session, i *event.InteractionCreate, _ *interaction.MessageComponentData, resp *ResponseBuilder) {
+	p.move(s, i, resp, 1)
+}
+
+func (p *Paginator) move(s bot.Session, i *event.InteractionCreate, resp *ResponseBuilder, delta int) {
+	if InteractionUserID(i) != p.userID {
+		if err := resp.IsEphemeral().SetMessage(p.forbidden).Send(); err != nil {
+			s.Logger().Error("sending paginator forbidden message", "error", err)
+		}
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := p.current + delta
+	if n < 0 || n >= p.source.Len() {
+		n = p.current
+	}
+	old := p.current
+	p.current = n
+	if err := p.render(resp.IsUpdate(), false); err != nil {
+		p.current = old
+		s.Logger().Error("rendering page", "error", err, "page", n)
+		err = resp.NotUpdate().IsEphemeral().SetMessage("Impossible to display this page.").Send()
+		if err != nil {
+			s.Logger().Error("sending paginator error", "error", err)
+		}
+		return
+	}
+	if err := resp.Send(); err != nil {
+		p.current = old
+		s.Logger().Error("updating paginator", "error", err)
+		return
+	}
+	// the token of the latest interaction is valid longer
+	p.resp = resp
+	p.timer.Reset(p.expiration())
+}
+
+// expiration returns the duration before disabling the buttons
+func (p *Paginator) expiration() time.Duration {
+	return min(p.timeout, interactionTokenLifetime)
+}
+
+// expire disables the buttons and unregisters the handlers
+func (p *Paginator) expire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.unbind()
+	// edits the message of the latest interaction (the original response or the message of the component)
+	resp := p.resp.new().IsEdit()
+	if err := p.render(resp, true); err != nil {
+		p.resp.session.Logger().Error("rendering page", "error", err, "page", p.current)
+		return
+	}
+	if err := resp.Send(); err != nil {
+		p.resp.session.Logger().Error("disabling paginator", "error", err)
+	}
+}
+
+func (p *Paginator) unbind() {
+	p.router.RemoveComponent(p.prevID)
+	p.router.RemoveComponent(p.nextID)
+}
+
+// InteractionUserID returns the ID of the user who created the interaction (in a guild or not)
+func InteractionUserID(i *event.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
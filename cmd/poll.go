@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nyttikord/gokord/channel"
+	"github.com/nyttikord/gokord/component"
+)
+
+const (
+	MaxPollAnswers        = 10
+	MaxPollQuestionLength = 300
+	MaxPollAnswerLength   = 55
+	MaxPollDuration       = 32 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidPollQuestion = errors.New("poll question must be between 1 and 300 characters long")
+	ErrInvalidPollAnswers  = errors.New("poll must contain between 1 and 10 answers")
+	ErrInvalidPollAnswer   = errors.New("poll answer must be between 1 and 55 characters long")
+	ErrInvalidPollDuration = errors.New("poll duration must be between 1 hour and 32 days")
+	ErrPollNotEditable     = errors.New("poll cannot be added while editing a response")
+)
+
+type PollBuilder interface {
+	// AddAnswer to the PollBuilder (up to 10), emoji may be nil
+	AddAnswer(text string, emoji *component.Emoji) PollBuilder
+	// AllowMultiselect lets users choose several answers
+	AllowMultiselect() PollBuilder
+	// SetDuration of the PollBuilder (rounded to the hour)
+	SetDuration(d time.Duration) PollBuilder
+	// Poll returns the poll understandable by Discord
+	Poll() (*channel.Poll, error)
+}
+
+// pollCreator represents a generic poll
+type pollCreator struct {
+	Question    string
+	Answers     []channel.PollAnswer
+	Multiselect bool
+	Duration    time.Duration
+}
+
+// NewPoll creates a new PollBuilder lasting one day
+func NewPoll(question string) PollBuilder {
+	return &pollCreator{
+		Question: question,
+		Duration: 24 * time.Hour,
+	}
+}
+
+// AddAnswer to the pollCreator
+func (p *pollCreator) AddAnswer(text string, emoji *component.Emoji) PollBuilder {
+	p.Answers = append(p.Answers, channel.PollAnswer{Media: &channel.PollMedia{Text: text, Emoji: emoji}})
+	return p
+}
+
+// AllowMultiselect lets users choose several answers of the pollCreator
+func (p *pollCreator) AllowMultiselect() PollBuilder {
+	p.Multiselect = true
+	return p
+}
+
+// SetDuration of the pollCreator
+func (p *pollCreator) SetDuration(d time.Duration) PollBuilder {
+	p.Duration = d
+	return p
+}
+
+// Poll validates the pollCreator and turns it into a channel.Poll
+func (p *pollCreator) Poll() (*channel.Poll, error) {
+	l := utf8.RuneCountInString(p.Question)
+	if l == 0 || l > MaxPollQuestionLength {
+		return nil, ErrInvalidPollQuestion
+	}
+	if len(p.Answers) == 0 || len(p.Answers) > MaxPollAnswers {
+		return nil, ErrInvalidPollAnswers
+	}
+	for _, a := range p.Answers {
+		l = utf8.RuneCountInString(a.Media.Text)
+		if l == 0 || l > MaxPollAnswerLength {
+			return nil, ErrInvalidPollAnswer
+		}
+	}
+	hours := int(p.Duration.Round(time.Hour).Hours())
+	if hours < 1 || p.Duration > MaxPollDuration {
+		return nil, ErrInvalidPollDuration
+	}
+	return &channel.Poll{
+		Question:         channel.PollMedia{Text: p.Question},
+		Answers:          p.Answers,
+		AllowMultiselect: p.Multiselect,
+		LayoutType:       channel.PollLayoutTypeDefault,
+		Duration:         hours,
+	}, nil
+}
@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/interaction"
+)
+
+var (
+	ErrUnclosedQuote        = errors.New("unclosed quote")
+	ErrMissingArgument      = errors.New("missing argument")
+	ErrInvalidArgument      = errors.New("invalid argument")
+	ErrTooManyArguments     = errors.New("too many arguments")
+	ErrUnsupportedArgument  = errors.New("option cannot be used with a prefix")
+	ErrPrefixSubCmdNotFound = errors.New("subcommand not found")
+)
+
+var (
+	userMentionRegex    = regexp.MustCompile(`^<@!?(\d+)>$`)
+	roleMentionRegex    = regexp.MustCompile(`^<@&(\d+)>$`)
+	channelMentionRegex = regexp.MustCompile(`^<#(\d+)>$`)
+	snowflakeRegex      = regexp.MustCompile(`^\d+$`)
+)
+
+// SplitArgs splits the arguments of a prefix command separated by spaces.
+// Text between double quotes (or single quotes) is a single argument, and \ escapes the next character.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	var quote rune
+	inArg, escaped := false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			inArg, escaped = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			inArg = true
+			quote = r
+		case r == ' ' || r == '\n' || r == '\t':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			inArg = true
+			cur.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, ErrUnclosedQuote
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+// ParseArgs parses the arguments of a prefix command (without the name of the command) with the options of the
+// CommandBuilder.
+// It returns the options like Discord would send them for the slash command: use them to create an OptionMap.
+//
+// If the CommandBuilder has subcommands, the first argument is the name of the subcommand.
+// Arguments are positional, and the last string option takes the remaining arguments.
+//
+// Users, roles and channels are mentions or IDs, mentionables are user or role mentions, and booleans are
+// true/false, yes/no, on/off or 1/0.
+// Attachments are not supported.
+func ParseArgs(c CommandBuilder, args []string) ([]*interaction.CommandInteractionDataOption, error) {
+	options := c.ApplicationCommand().Options
+	if !c.HasSub() {
+		return parseOptions(options, args)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: subcommand", ErrMissingArgument)
+	}
+	for _, o := range options {
+		if o.Type != types.CommandOptionSubCommand || !strings.EqualFold(o.Name, args[0]) {
+			continue
+		}
+		opts, err := parseOptions(o.Options, args[1:])
+		if err != nil {
+			return nil, err
+		}
+		return []*interaction.CommandInteractionDataOption{
+			{Name: o.Name, Type: types.CommandOptionSubCommand, Options: opts},
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrPrefixSubCmdNotFound, args[0])
+}
+
+func parseOptions(options []*interaction.CommandOption, args []string) ([]*interaction.CommandInteractionDataOption, error) {
+	parsed := make([]*interaction.CommandInteractionDataOption, 0, len(options))
+	for n, o := range options {
+		if len(args) == 0 {
+			if o.Required {
+				return nil, fmt.Errorf("%w: %s", ErrMissingArgument, o.Name)
+			}
+			continue
+		}
+		arg := args[0]
+		args = args[1:]
+		if o.Type == types.CommandOptionString && n == len(options)-1 && len(args) > 0 {
+			arg = strings.Join(append([]string{arg}, args...), " ")
+			args = nil
+		}
+		v, err := parseOption(o, arg)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, &interaction.CommandInteractionDataOption{Name: o.Name, Type: o.Type, Value: v})
+	}
+	if len(args) > 0 {
+		return nil, ErrTooManyArguments
+	}
+	return parsed, nil
+}
+
+// parseOption returns the value of the option with the type used by Discord
+func parseOption(o *interaction.CommandOption, arg string) (any, error) {
+	invalid := func(expected string) error {
+		return fmt.Errorf("%w: %s must be %s", ErrInvalidArgument, o.Name, expected)
+	}
+	if len(o.Choices) > 0 {
+		for _, ch := range o.Choices {
+			if strings.EqualFold(ch.Name, arg) || fmt.Sprint(ch.Value) == arg {
+				arg = fmt.Sprint(ch.Value)
+				break
+			}
+		}
+	}
+	var v any
+	switch o.Type {
+	case types.CommandOptionString:
+		v = arg
+	case types.CommandOptionInteger:
+		i, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return nil, invalid("an integer")
+		}
+		// Discord sends numbers as JSON numbers
+		v = float64(i)
+	case types.CommandOptionNumber:
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, invalid("a number")
+		}
+		v = f
+	case types.CommandOptionBoolean:
+		switch strings.ToLower(arg) {
+		case "true", "yes", "on", "1":
+			v = true
+		case "false", "no", "off", "0":
+			v = false
+		default:
+			return nil, invalid("true or false")
+		}
+	case types.CommandOptionUser:
+		id, ok := parseMention(arg, userMentionRegex)
+		if !ok {
+			return nil, invalid("a user")
+		}
+		v = id
+	case types.CommandOptionRole:
+		id, ok := parseMention(arg, roleMentionRegex)
+		if !ok {
+			return nil, invalid("a role")
+		}
+		v = id
+	case types.CommandOptionChannel:
+		id, ok := parseMention(arg, channelMentionRegex)
+		if !ok {
+			return nil, invalid("a channel")
+		}
+		v = id
+	case types.CommandOptionMentionable:
+		id, ok := parseMention(arg, userMentionRegex)
+		if !ok {
+			id, ok = parseMention(arg, roleMentionRegex)
+		}
+		if !ok {
+			return nil, invalid("a user or a role")
+		}
+		v = id
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedArgument, o.Name)
+	}
+	if err := checkOptionBounds(o, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// checkOptionBounds checks the choices, the bounds and the length of the value like Discord does
+func checkOptionBounds(o *interaction.CommandOption, v any) error {
+	if len(o.Choices) > 0 {
+		valid := false
+		for _, ch := range o.Choices {
+			if fmt.Sprint(ch.Value) == fmt.Sprint(v) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("%w: %s is not a valid choice", ErrInvalidArgument, o.Name)
+		}
+	}
+	switch val := v.(type) {
+	case float64:
+		if o.MinValue != nil && val < *o.MinValue {
+			return fmt.Errorf("%w: %s must be at least %v", ErrInvalidArgument, o.Name, *o.MinValue)
+		}
+		if o.MaxValue != 0 && val > o.MaxValue {
+			return fmt.Errorf("%w: %s must be at most %v", ErrInvalidArgument, o.Name, o.MaxValue)
+		}
+	case string:
+		if o.Type != types.CommandOptionString {
+			return nil
+		}
+		l := len([]rune(val))
+		if o.MinLength != nil && l < *o.MinLength {
+			return fmt.Errorf("%w: %s must contain at least %d characters", ErrInvalidArgument, o.Name, *o.MinLength)
+		}
+		if o.MaxLength != 0 && l > o.MaxLength {
+			return fmt.Errorf("%w: %s must contain at most %d characters", ErrInvalidArgument, o.Name, o.MaxLength)
+		}
+	}
+	return nil
+}
+
+// parseMention returns the ID contained in the mention (or the ID itself)
+func parseMention(arg string, re *regexp.Regexp) (string, bool) {
+	if m := re.FindStringSubmatch(arg); m != nil {
+		return m[1], true
+	}
+	return arg, snowflakeRegex.MatchString(arg)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/interaction"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+		err  error
+	}{
+		{"", nil, nil},
+		{"   ", nil, nil},
+		{"a b  c", []string{"a", "b", "c"}, nil},
+		{"a\tb\nc", []string{"a", "b", "c"}, nil},
+		{`"hello world" foo`, []string{"hello world", "foo"}, nil},
+		{`'it is' "a 'quote'"`, []string{"it is", "a 'quote'"}, nil},
+		{`a"b c"d`, []string{"ab cd"}, nil},
+		{`""`, []string{""}, nil},
+		{`hello\ world`, []string{"hello world"}, nil},
+		{`\"a b`, []string{`"a`, "b"}, nil},
+		{`"a \" b"`, []string{`a " b`}, nil},
+		{`"unclosed`, nil, ErrUnclosedQuote},
+		{`'unclosed "`, nil, ErrUnclosedQuote},
+	}
+	for _, tt := range tests {
+		got, err := SplitArgs(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("SplitArgs(%q): got error %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SplitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	simple := New("ban", "Ban a user").
+		AddOption(NewOption(types.CommandOptionUser, "user", "User").IsRequired()).
+		AddOption(NewOption(types.CommandOptionInteger, "days", "Days").SetMinValue(0).SetMaxValue(7)).
+		AddOption(NewOption(types.CommandOptionString, "reason", "Reason"))
+	subs := New("config", "Config").
+		AddSub(New("set", "Set").
+			AddOption(NewOption(types.CommandOptionBoolean, "enabled", "Enabled").IsRequired())).
+		AddSub(New("show", "Show"))
+	opt := func(name string, t types.CommandOption, v any) *interaction.CommandInteractionDataOption {
+		return &interaction.CommandInteractionDataOption{Name: name, Type: t, Value: v}
+	}
+	tests := []struct {
+		name string
+		cmd  CommandBuilder
+		args []string
+		want []*interaction.CommandInteractionDataOption
+		err  error
+	}{
+		{"required only", simple, []string{"<@!42>"},
+			[]*interaction.CommandInteractionDataOption{opt("user", types.CommandOptionUser, "42")}, nil},
+		{"last string takes the rest", simple, []string{"42", "3", "being", "rude"},
+			[]*interaction.CommandInteractionDataOption{
+				opt("user", types.CommandOptionUser, "42"),
+				opt("days", types.CommandOptionInteger, 3.),
+				opt("reason", types.CommandOptionString, "being rude"),
+			}, nil},
+		{"missing required", simple, nil, nil, ErrMissingArgument},
+		{"invalid user", simple, []string{"<#42>"}, nil, ErrInvalidArgument},
+		{"invalid integer", simple, []string{"42", "three"}, nil, ErrInvalidArgument},
+		{"out of bounds", simple, []string{"42", "8"}, nil, ErrInvalidArgument},
+		{"subcommand", subs, []string{"SET", "yes"},
+			[]*interaction.CommandInteractionDataOption{{
+				Name: "set", Type: types.CommandOptionSubCommand,
+				Options: []*interaction.CommandInteractionDataOption{
+					opt("enabled", types.CommandOptionBoolean, true),
+				},
+			}}, nil},
+		{"subcommand without options", subs, []string{"show"},
+			[]*interaction.CommandInteractionDataOption{{
+				Name: "show", Type: types.CommandOptionSubCommand,
+				Options: []*interaction.CommandInteractionDataOption{},
+			}}, nil},
+		{"too many arguments", subs, []string{"show", "more"}, nil, ErrTooManyArguments},
+		{"missing subcommand", subs, nil, nil, ErrMissingArgument},
+		{"unknown subcommand", subs, []string{"reset"}, nil, ErrPrefixSubCmdNotFound},
+		{"unsupported option", New("upload", "Upload").
+			AddOption(NewOption(types.CommandOptionAttachment, "file", "File")), []string{"x"},
+			nil, ErrUnsupportedArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArgs(tt.cmd, tt.args)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOption(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  types.CommandOption
+		arg  string
+		want any
+		err  error
+	}{
+		{"bool yes", types.CommandOptionBoolean, "Yes", true, nil},
+		{"bool off", types.CommandOptionBoolean, "off", false, nil},
+		{"bool invalid", types.CommandOptionBoolean, "maybe", nil, ErrInvalidArgument},
+		{"number", types.CommandOptionNumber, "1.5", 1.5, nil},
+		{"role mention", types.CommandOptionRole, "<@&12>", "12", nil},
+		{"role id", types.CommandOptionRole, "12", "12", nil},
+		{"channel mention", types.CommandOptionChannel, "<#12>", "12", nil},
+		{"channel invalid", types.CommandOptionChannel, "#general", nil, ErrInvalidArgument},
+		{"mentionable user", types.CommandOptionMentionable, "<@12>", "12", nil},
+		{"mentionable role", types.CommandOptionMentionable, "<@&12>", "12", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOption(&interaction.CommandOption{Name: "opt", Type: tt.typ}, tt.arg)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckOptionBounds(t *testing.T) {
+	minValue, minLength := 1., 2
+	choices := []*interaction.CommandOptionChoice{{Name: "English", Value: "en"}, {Name: "One", Value: 1.}}
+	tests := []struct {
+		name  string
+		opt   *interaction.CommandOption
+		value any
+		valid bool
+	}{
+		{"no bounds", &interaction.CommandOption{Type: types.CommandOptionNumber}, -5., true},
+		{"min value", &interaction.CommandOption{Type: types.CommandOptionNumber, MinValue: &minValue}, 1., true},
+		{"under min value", &interaction.CommandOption{Type: types.CommandOptionNumber, MinValue: &minValue}, .5, false},
+		{"max value", &interaction.CommandOption{Type: types.CommandOptionInteger, MaxValue: 10}, 10., true},
+		{"over max value", &interaction.CommandOption{Type: types.CommandOptionInteger, MaxValue: 10}, 11., false},
+		{"min length", &interaction.CommandOption{Type: types.CommandOptionString, MinLength: &minLength}, "ab", true},
+		{"under min length", &interaction.CommandOption{Type: types.CommandOptionString, MinLength: &minLength}, "a", false},
+		{"max length in runes", &interaction.CommandOption{Type: types.CommandOptionString, MaxLength: 3}, "été", true},
+		{"over max length", &interaction.CommandOption{Type: types.CommandOptionString, MaxLength: 3}, "abcd", false},
+		{"length of an ID", &interaction.CommandOption{Type: types.CommandOptionUser, MaxLength: 3}, "12345", true},
+		{"string choice", &interaction.CommandOption{Type: types.CommandOptionString, Choices: choices}, "en", true},
+		{"number choice", &interaction.CommandOption{Type: types.CommandOptionNumber, Choices: choices}, 1., true},
+		{"not a choice", &interaction.CommandOption{Type: types.CommandOptionString, Choices: choices}, "fr", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOptionBounds(tt.opt, tt.value)
+			if tt.valid && err != nil {
+				t.Errorf("got error %v", err)
+			} else if !tt.valid && !errors.Is(err, ErrInvalidArgument) {
+				t.Errorf("got error %v, want %v", err, ErrInvalidArgument)
+			}
+		})
+	}
+}
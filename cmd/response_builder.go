@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/nyttikord/gokord/bot"
@@ -13,6 +16,17 @@ import (
 	"github.com/nyttikord/gokord/interaction"
 )
 
+// ErrFlagsNotEditable is returned when SuppressEmbeds or IsSilent is used while editing a response (except if they were
+// set before sending the deferred response)
+var ErrFlagsNotEditable = errors.New("suppress embeds and silent flags cannot be set while editing a response")
+
+// ErrLayoutNotEditable is returned when layout components are added while editing a response that was not sent with
+// layout components by the ResponseBuilder: the webhook edit cannot set channel.MessageFlagsIsComponentsV2
+var ErrLayoutNotEditable = errors.New("layout components cannot be added while editing a response without them")
+
+// Author of the bot, used by Branding if Branding.AuthorName is empty
+//
+// Deprecated: use Branding.AuthorName (set by gokord.Bot).
 var Author string
 
 // ResponseBuilder helps to response to slash commands
@@ -21,21 +35,52 @@ type ResponseBuilder struct {
 	ephemeral  bool
 	deferred   bool
 	edit       bool
+	followup   bool
+	update     bool
 	modal      bool
 	components []component.Component
+	builders   []ComponentBuilder
+	layout     bool
 	embeds     []*channel.MessageEmbed
 	files      []*channel.File
+	fileDescs  []string
 	title      string
 	customID   string
+	mentions   *channel.MessageAllowedMentions
+	flags      channel.MessageFlags
+	tts        bool
+	poll       *channel.Poll
+	choices    []*interaction.CommandOptionChoice
 	//
 	interaction *event.InteractionCreate
 	session     bot.Session
+	router      *Router
+	branding    *Branding
+	services    *Services
+	ctx         context.Context
+	// message replied to by the ResponseBuilder (prefix commands only)
+	message *channel.Message
+	// reply sent to message
+	reply *channel.Message
+	// responder sends the initial response instead of the API (HTTP interactions only)
+	responder func(r *interaction.Response) error
+	// flags sent with the deferred response
+	sentFlags channel.MessageFlags
+	// custom IDs of the handlers bound by the components
+	bound       []string
+	handlersTTL time.Duration
+	err         error
 }
 
+// NewResponseBuilder creates a new ResponseBuilder.
+// By default, only users can be mentioned: see SetAllowedMentions to modify this behavior
 func NewResponseBuilder(s bot.Session, i *event.InteractionCreate) *ResponseBuilder {
 	return &ResponseBuilder{
 		interaction: i,
 		session:     s,
+		mentions: &channel.MessageAllowedMentions{
+			Parse: []channel.AllowedMentionType{channel.AllowedMentionTypeUsers},
+		},
 	}
 }
 
@@ -49,56 +94,149 @@ func formatInteractionResponse(r interface{}) string {
 
 // Send the response
 func (res *ResponseBuilder) Send() error {
-	if res.edit {
-		cmps := make([]component.Message, len(res.components))
-		for i, c := range res.components {
-			cmps[i] = c.(component.Message)
-		}
-		wb := &channel.WebhookEdit{
-			Content:    &res.content,
-			Components: &cmps,
-			Embeds:     &res.embeds,
-			Files:      res.files,
-		}
-		_, err := res.session.InteractionAPI().ResponseEdit(res.interaction.Interaction, wb)
-		if err != nil {
-			fmt.Println(formatInteractionResponse(wb))
-		}
+	if res.err != nil {
+		return res.err
+	}
+	if err := validateEmbeds(res.embeds); err != nil {
+		return err
+	}
+	if err := res.buildComponents(); err != nil {
 		return err
 	}
+	if res.message != nil {
+		return res.sendReply()
+	}
+	if res.edit {
+		return res.sendEdit()
+	}
+	if res.followup {
+		return res.sendFollowup()
+	}
 
 	r := &interaction.Response{
 		Type: types.InteractionResponseChannelMessageWithSource,
 		Data: &interaction.ResponseData{
-			Content:    res.content,
-			Components: res.components,
-			Embeds:     res.embeds,
-			Files:      res.files,
-			CustomID:   res.customID,
-			Title:      res.title,
+			Content:         res.content,
+			Components:      res.components,
+			Embeds:          res.embeds,
+			Files:           res.files,
+			Attachments:     res.attachments(),
+			CustomID:        res.customID,
+			Title:           res.title,
+			AllowedMentions: res.mentions,
+			Flags:           res.messageFlags(),
+			TTS:             res.tts,
+			Poll:            res.poll,
 		},
 	}
 	if res.deferred {
 		r.Type = types.InteractionResponseDeferredChannelMessageWithSource
 	}
-	if res.ephemeral {
-		r.Data.Flags = channel.MessageFlagsEphemeral
+	if res.update {
+		r.Type = types.InteractionResponseUpdateMessage
 	}
 	if res.modal {
 		r.Type = types.InteractionResponseModal
 	}
+	if res.choices != nil {
+		r.Type = types.InteractionApplicationCommandAutocompleteResult
+		r.Data = &interaction.ResponseData{Choices: res.choices}
+	}
 
-	if err := res.session.InteractionAPI().Respond(res.interaction.Interaction, r); err != nil {
-		fmt.Println(formatInteractionResponse(r))
+	if err := res.respond(r); err != nil {
+		res.session.Logger().Debug("responding to interaction", "error", err, "response", formatInteractionResponse(r))
 		return err
 	}
 
 	if res.deferred {
+		res.sentFlags = res.messageFlags()
 		res.IsEdit()
 	}
 	return nil
 }
 
+// respond sends the initial response with the responder if it is set, or with the API
+func (res *ResponseBuilder) respond(r *interaction.Response) error {
+	if res.responder != nil {
+		return res.responder(r)
+	}
+	return res.session.InteractionAPI().Respond(res.interaction.Interaction, r)
+}
+
+// acknowledge the interaction without modifying the message (message components and modals only)
+func (res *ResponseBuilder) acknowledge() error {
+	return res.respond(&interaction.Response{Type: types.InteractionResponseDeferredMessageUpdate})
+}
+
+// sendEdit edits the original response
+func (res *ResponseBuilder) sendEdit() error {
+	if res.poll != nil {
+		return ErrPollNotEditable
+	}
+	// the webhook edit cannot change the flags of the message
+	if res.flags&^res.sentFlags != 0 {
+		return ErrFlagsNotEditable
+	}
+	if res.layout && res.sentFlags&channel.MessageFlagsIsComponentsV2 == 0 {
+		return ErrLayoutNotEditable
+	}
+	cmps, err := res.messageComponents()
+	if err != nil {
+		return err
+	}
+	wb := &channel.WebhookEdit{
+		Content:         &res.content,
+		Components:      &cmps,
+		Embeds:          &res.embeds,
+		Files:           res.files,
+		Attachments:     res.attachments(),
+		AllowedMentions: res.mentions,
+	}
+	_, err = res.session.InteractionAPI().ResponseEdit(res.interaction.Interaction, wb)
+	if err != nil {
+		res.session.Logger().Debug("editing interaction response", "error", err, "edit", formatInteractionResponse(wb))
+	}
+	return err
+}
+
+// sendFollowup sends a new message after the original response
+func (res *ResponseBuilder) sendFollowup() error {
+	cmps, err := res.messageComponents()
+	if err != nil {
+		return err
+	}
+	params := &channel.WebhookParams{
+		Content:         res.content,
+		TTS:             res.tts,
+		Files:           res.files,
+		Components:      cmps,
+		Embeds:          res.embeds,
+		AllowedMentions: res.mentions,
+		Flags:           res.messageFlags(),
+		Poll:            res.poll,
+	}
+	if at := res.attachments(); at != nil {
+		params.Attachments = *at
+	}
+	_, err = res.session.InteractionAPI().FollowupMessageCreate(res.interaction.Interaction, true, params)
+	if err != nil {
+		res.session.Logger().Debug("sending followup", "error", err, "message", formatInteractionResponse(params))
+	}
+	return err
+}
+
+// messageFlags returns the flags of the message
+func (res *ResponseBuilder) messageFlags() channel.MessageFlags {
+	flags := res.flags
+	if res.ephemeral {
+		flags |= channel.MessageFlagsEphemeral
+	}
+	if res.layout {
+		flags |= channel.MessageFlagsIsComponentsV2
+	}
+	return flags
+}
+
 func (res *ResponseBuilder) IsEphemeral() *ResponseBuilder {
 	res.ephemeral = true
 	return res
@@ -111,6 +249,8 @@ func (res *ResponseBuilder) NotEphemeral() *ResponseBuilder {
 
 func (res *ResponseBuilder) IsDeferred() *ResponseBuilder {
 	res.NotEdit()
+	res.NotFollowup()
+	res.NotUpdate()
 	res.NotModal()
 	res.deferred = true
 	return res
@@ -123,6 +263,8 @@ func (res *ResponseBuilder) NotDeferred() *ResponseBuilder {
 
 func (res *ResponseBuilder) IsEdit() *ResponseBuilder {
 	res.NotDeferred()
+	res.NotFollowup()
+	res.NotUpdate()
 	res.NotModal()
 	res.edit = true
 	return res
@@ -133,9 +275,41 @@ func (res *ResponseBuilder) NotEdit() *ResponseBuilder {
 	return res
 }
 
+// IsFollowup sends a new message after the original response (the interaction must have been responded)
+func (res *ResponseBuilder) IsFollowup() *ResponseBuilder {
+	res.NotDeferred()
+	res.NotEdit()
+	res.NotUpdate()
+	res.NotModal()
+	res.followup = true
+	return res
+}
+
+func (res *ResponseBuilder) NotFollowup() *ResponseBuilder {
+	res.followup = false
+	return res
+}
+
+// IsUpdate edits the message containing the component that created the interaction (message components only)
+func (res *ResponseBuilder) IsUpdate() *ResponseBuilder {
+	res.NotDeferred()
+	res.NotEdit()
+	res.NotFollowup()
+	res.NotModal()
+	res.update = true
+	return res
+}
+
+func (res *ResponseBuilder) NotUpdate() *ResponseBuilder {
+	res.update = false
+	return res
+}
+
 func (res *ResponseBuilder) IsModal() *ResponseBuilder {
 	res.NotDeferred()
 	res.NotEdit()
+	res.NotFollowup()
+	res.NotUpdate()
 	res.NotEphemeral()
 	res.modal = true
 	return res
@@ -146,6 +320,73 @@ func (res *ResponseBuilder) NotModal() *ResponseBuilder {
 	return res
 }
 
+// SetAllowedMentions of the response.
+// If it is nil, every mention is allowed (including @everyone and roles!)
+func (res *ResponseBuilder) SetAllowedMentions(m *channel.MessageAllowedMentions) *ResponseBuilder {
+	res.mentions = m
+	return res
+}
+
+// AllowEveryone allows the response to mention @everyone and @here
+func (res *ResponseBuilder) AllowEveryone() *ResponseBuilder {
+	return res.allowMention(channel.AllowedMentionTypeEveryone)
+}
+
+// AllowRoleMentions allows the response to mention roles
+func (res *ResponseBuilder) AllowRoleMentions() *ResponseBuilder {
+	return res.allowMention(channel.AllowedMentionTypeRoles)
+}
+
+// DisableMentions prevents the response from mentioning anyone
+func (res *ResponseBuilder) DisableMentions() *ResponseBuilder {
+	res.mentions = &channel.MessageAllowedMentions{Parse: []channel.AllowedMentionType{}}
+	return res
+}
+
+func (res *ResponseBuilder) allowMention(t channel.AllowedMentionType) *ResponseBuilder {
+	if res.mentions == nil {
+		// everything is already allowed
+		return res
+	}
+	if !slices.Contains(res.mentions.Parse, t) {
+		res.mentions.Parse = append(res.mentions.Parse, t)
+	}
+	return res
+}
+
+// SuppressEmbeds of the links in the response.
+// It cannot be added when editing a response (see ErrFlagsNotEditable).
+func (res *ResponseBuilder) SuppressEmbeds() *ResponseBuilder {
+	res.flags |= channel.MessageFlagsSuppressEmbeds
+	return res
+}
+
+// IsSilent sends the response without triggering push and desktop notifications.
+// It cannot be added when editing a response (see ErrFlagsNotEditable).
+func (res *ResponseBuilder) IsSilent() *ResponseBuilder {
+	res.flags |= channel.MessageFlagsSuppressNotifications
+	return res
+}
+
+// IsTTS sends the response as a text-to-speech message
+func (res *ResponseBuilder) IsTTS() *ResponseBuilder {
+	res.tts = true
+	return res
+}
+
+// SetPoll attached to the response.
+// If the poll is invalid, the error is returned by Send.
+// A poll cannot be added while editing a response.
+func (res *ResponseBuilder) SetPoll(p PollBuilder) *ResponseBuilder {
+	poll, err := p.Poll()
+	if err != nil {
+		res.err = errors.Join(res.err, err)
+		return res
+	}
+	res.poll = poll
+	return res
+}
+
 func (res *ResponseBuilder) SetMessage(s string) *ResponseBuilder {
 	res.content = s
 	return res
@@ -161,16 +402,19 @@ func (res *ResponseBuilder) SetCustomID(s string) *ResponseBuilder {
 	return res
 }
 
+// AddEmbed to the response.
+// The Branding is applied to the embed.
 func (res *ResponseBuilder) AddEmbed(e *channel.MessageEmbed) *ResponseBuilder {
-	t := time.Now()
-	e.Footer = &channel.MessageEmbedFooter{
-		Text:    "by " + Author,
-		IconURL: res.session.SessionState().User().AvatarURL(""),
-	}
-	e.Timestamp = t.Format(time.RFC3339)
-	e.Author = &channel.MessageEmbedAuthor{
-		Name: res.session.SessionState().User().Username,
+	b := res.branding
+	if b == nil {
+		b = DefaultBranding
 	}
+	b.apply(res.session, res.interaction.GuildID, e)
+	return res.AddEmbedWithoutBranding(e)
+}
+
+// AddEmbedWithoutBranding adds the embed to the response without modifying it
+func (res *ResponseBuilder) AddEmbedWithoutBranding(e *channel.MessageEmbed) *ResponseBuilder {
 	if res.embeds == nil {
 		res.embeds = []*channel.MessageEmbed{e}
 	} else {
@@ -179,16 +423,227 @@ func (res *ResponseBuilder) AddEmbed(e *channel.MessageEmbed) *ResponseBuilder {
 	return res
 }
 
-func (res *ResponseBuilder) AddFile(f *channel.File) *ResponseBuilder {
-	if res.files == nil {
-		res.files = []*channel.File{f}
-	} else {
-		res.files = append(res.files, f)
+// AddEmbedBuilder to the response.
+// The Branding is applied if EmbedBuilder.IsBranded returns true.
+// If the embed is invalid, the error is returned by Send.
+func (res *ResponseBuilder) AddEmbedBuilder(eb EmbedBuilder) *ResponseBuilder {
+	e, err := eb.Embed()
+	if err != nil {
+		res.err = errors.Join(res.err, err)
+		return res
+	}
+	if eb.IsBranded() {
+		return res.AddEmbed(e)
 	}
+	return res.AddEmbedWithoutBranding(e)
+}
+
+// AddFile to the response
+func (res *ResponseBuilder) AddFile(f *channel.File) *ResponseBuilder {
+	res.files = append(res.files, f)
+	res.fileDescs = append(res.fileDescs, "")
 	return res
 }
 
+// AddFileBuilder to the response.
+// The file is read when the response is sent
+func (res *ResponseBuilder) AddFileBuilder(fb FileBuilder) *ResponseBuilder {
+	f, desc := fb.toFile()
+	res.files = append(res.files, f)
+	res.fileDescs = append(res.fileDescs, desc)
+	return res
+}
+
+// attachments returns the metadata of the files of the response.
+// It returns nil if there is no metadata to send
+func (res *ResponseBuilder) attachments() *[]*channel.MessageAttachment {
+	if !slices.ContainsFunc(res.fileDescs, func(d string) bool { return d != "" }) {
+		return nil
+	}
+	at := make([]*channel.MessageAttachment, len(res.files))
+	for i, f := range res.files {
+		at[i] = &channel.MessageAttachment{
+			ID:          strconv.Itoa(i),
+			Filename:    f.Name,
+			Description: res.fileDescs[i],
+		}
+	}
+	return &at
+}
+
+// SetComponents of the response.
+// Components added with AddComponent are appended after them
 func (res *ResponseBuilder) SetComponents(c []component.Component) *ResponseBuilder {
 	res.components = c
 	return res
 }
+
+// AddComponent to the response.
+// Handlers bound to the component are registered when the response is sent
+func (res *ResponseBuilder) AddComponent(c ComponentBuilder) *ResponseBuilder {
+	res.builders = append(res.builders, c)
+	return res
+}
+
+// SetRouter used to register handlers bound to components (already set by gokord)
+func (res *ResponseBuilder) SetRouter(r *Router) *ResponseBuilder {
+	res.router = r
+	return res
+}
+
+// SetBranding applied to embeds (already set by gokord).
+// If it is nil, DefaultBranding is used
+func (res *ResponseBuilder) SetBranding(b *Branding) *ResponseBuilder {
+	res.branding = b
+	return res
+}
+
+// SetServices available to the handler with Resolve (already set by gokord)
+func (res *ResponseBuilder) SetServices(s *Services) *ResponseBuilder {
+	res.services = s
+	return res
+}
+
+// SetContext of the handler (already set by gokord)
+func (res *ResponseBuilder) SetContext(ctx context.Context) *ResponseBuilder {
+	res.ctx = ctx
+	return res
+}
+
+// SetResponder sending the initial response instead of the API (already set by gokord for HTTP interactions)
+func (res *ResponseBuilder) SetResponder(fn func(r *interaction.Response) error) *ResponseBuilder {
+	res.responder = fn
+	return res
+}
+
+// AddChoices to the response of an autocomplete interaction (the response only contains the choices)
+func (res *ResponseBuilder) AddChoices(choices ...CommandChoiceBuilder) *ResponseBuilder {
+	if res.choices == nil {
+		res.choices = make([]*interaction.CommandOptionChoice, 0, len(choices))
+	}
+	for _, ch := range choices {
+		res.choices = append(res.choices, ch.toDiscordChoice())
+	}
+	return res
+}
+
+// Interaction returns the interaction responded to by the ResponseBuilder
+func (res *ResponseBuilder) Interaction() *event.InteractionCreate {
+	return res.interaction
+}
+
+// Context returns the context set with SetContext (context.Background if there is none).
+// It must be used by the handler instead of a global context.
+func (res *ResponseBuilder) Context() context.Context {
+	if res.ctx == nil {
+		return context.Background()
+	}
+	return res.ctx
+}
+
+// Services returns the Services set with SetServices (nil if there is none)
+func (res *ResponseBuilder) Services() *Services {
+	return res.services
+}
+
+// RemoveHandlers unregisters the handlers bound by the components of the ResponseBuilder (see ButtonBuilder.SetHandler
+// and SelectMenuBuilder.SetHandler).
+// It must be called when the components are not used anymore, because they are kept for the lifetime of the Router.
+func (res *ResponseBuilder) RemoveHandlers() {
+	if res.router == nil {
+		return
+	}
+	for _, id := range res.bound {
+		res.router.RemoveComponent(id)
+	}
+	res.bound = nil
+}
+
+// ExpireHandlers unregisters the handlers bound by the components added after the call once the duration is elapsed
+// (see RemoveHandlers)
+func (res *ResponseBuilder) ExpireHandlers(d time.Duration) *ResponseBuilder {
+	res.handlersTTL = d
+	return res
+}
+
+// new creates a new ResponseBuilder responding to the same interaction with the same configuration
+func (res *ResponseBuilder) new() *ResponseBuilder {
+	n := NewResponseBuilder(res.session, res.interaction).
+		SetRouter(res.router).
+		SetBranding(res.branding).
+		SetServices(res.services).
+		SetContext(res.ctx)
+	n.message = res.message
+	n.reply = res.reply
+	return n
+}
+
+// buildComponents validates components added with AddComponent, binds their handlers and appends them to the
+// components of the response
+func (res *ResponseBuilder) buildComponents() error {
+	if len(res.builders) == 0 {
+		return nil
+	}
+	layout := res.layout
+	rows := 0
+	size := len(res.components)
+	for _, b := range res.builders {
+		if b.IsLayout() {
+			layout = true
+		} else {
+			rows++
+		}
+		size += b.size()
+	}
+	if layout {
+		if size > MaxLayoutComponents {
+			return ErrTooManyComponents
+		}
+		if res.content != "" || len(res.embeds) > 0 {
+			return ErrLayoutWithContent
+		}
+	} else if rows+len(res.components) > MaxActionRows {
+		return ErrTooManyActionRows
+	}
+	cmps := make([]component.Component, len(res.builders))
+	for i, b := range res.builders {
+		c, err := b.Component()
+		if err != nil {
+			return err
+		}
+		cmps[i] = c
+	}
+	if res.router == nil {
+		return ErrRouterNotSet
+	}
+	var ids []string
+	for _, b := range res.builders {
+		ids = append(ids, b.bind(res.router)...)
+	}
+	res.bound = append(res.bound, ids...)
+	if res.handlersTTL > 0 && len(ids) > 0 {
+		router := res.router
+		time.AfterFunc(res.handlersTTL, func() {
+			for _, id := range ids {
+				router.RemoveComponent(id)
+			}
+		})
+	}
+	res.components = append(res.components, cmps...)
+	res.builders = nil
+	res.layout = layout
+	return nil
+}
+
+// messageComponents returns components of the response usable in a message
+func (res *ResponseBuilder) messageComponents() ([]component.Message, error) {
+	cmps := make([]component.Message, len(res.components))
+	for i, c := range res.components {
+		m, ok := c.(component.Message)
+		if !ok {
+			return nil, ErrNotMessageComponent
+		}
+		cmps[i] = m
+	}
+	return cmps, nil
+}
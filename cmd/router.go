@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"sync"
+
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/interaction"
+)
+
+type ComponentHandler func(s bot.Session, i *event.InteractionCreate, data *interaction.MessageComponentData, resp *ResponseBuilder)
+
+type ModalHandler func(s bot.Session, i *event.InteractionCreate, data *interaction.ModalSubmitData, resp *ResponseBuilder)
+
+// AutocompleteHandler responds to the autocompletion of the focused option with ResponseBuilder.AddChoices
+type AutocompleteHandler func(s bot.Session, i *event.InteractionCreate, focused *interaction.CommandInteractionDataOption, resp *ResponseBuilder)
+
+// Router links the custom ID of message components and modals to their handler
+type Router struct {
+	mu            sync.RWMutex
+	components    map[string]ComponentHandler
+	modals        map[string]ModalHandler
+	autocompletes map[string]AutocompleteHandler
+}
+
+// NewRouter creates a new empty Router
+func NewRouter() *Router {
+	return &Router{
+		components:    map[string]ComponentHandler{},
+		modals:        map[string]ModalHandler{},
+		autocompletes: map[string]AutocompleteHandler{},
+	}
+}
+
+// HandleComponent registers the handler called when the message component with the given custom ID is used.
+// It replaces the previous handler registered with this ID.
+func (r *Router) HandleComponent(id string, handler ComponentHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.components[id] = handler
+}
+
+// HandleModal registers the handler called when the modal with the given custom ID is submitted.
+// It replaces the previous handler registered with this ID.
+func (r *Router) HandleModal(id string, handler ModalHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modals[id] = handler
+}
+
+// HandleAutocomplete registers the handler called when the option of the command is autocompleted.
+// command is the full name of the command, including its subcommand (e.g. "config set").
+// It replaces the previous handler registered for this option.
+func (r *Router) HandleAutocomplete(command string, option string, handler AutocompleteHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.autocompletes[command+" "+option] = handler
+}
+
+// RemoveComponent unregisters the handler of the message component with the given custom ID
+func (r *Router) RemoveComponent(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.components, id)
+}
+
+// RemoveModal unregisters the handler of the modal with the given custom ID
+func (r *Router) RemoveModal(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.modals, id)
+}
+
+// Component returns the handler of the message component with the given custom ID
+func (r *Router) Component(id string) (ComponentHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.components[id]
+	return h, ok
+}
+
+// Modal returns the handler of the modal with the given custom ID
+func (r *Router) Modal(id string) (ModalHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.modals[id]
+	return h, ok
+}
+
+// Autocomplete returns the handler of the option of the command (see HandleAutocomplete)
+func (r *Router) Autocomplete(command string, option string) (AutocompleteHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.autocompletes[command+" "+option]
+	return h, ok
+}
+
+// FocusedOption returns the full name of the command (including its subcommand) and the option focused by the user in
+// the autocomplete interaction
+func FocusedOption(data *interaction.CommandInteractionData) (string, *interaction.CommandInteractionDataOption) {
+	name := data.Name
+	opts := data.Options
+	for len(opts) > 0 && (opts[0].Type == types.CommandOptionSubCommand || opts[0].Type == types.CommandOptionSubCommandGroup) {
+		name += " " + opts[0].Name
+		opts = opts[0].Options
+	}
+	for _, o := range opts {
+		if o.Focused {
+			return name, o
+		}
+	}
+	return name, nil
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var ErrServiceNotFound = errors.New("service not found")
+
+// Services is a container of services (repositories, HTTP clients, caches...) shared by handlers, indexed by their type.
+//
+// Register them with Provide or ProvideFunc, and retrieve them with Resolve.
+type Services struct {
+	mu       sync.RWMutex
+	services map[reflect.Type]*service
+}
+
+// service registered in Services
+type service struct {
+	once    sync.Once
+	value   any
+	err     error
+	factory func() (any, error)
+}
+
+// ServiceProvider gives access to Services.
+// It is implemented by Services and ResponseBuilder.
+type ServiceProvider interface {
+	Services() *Services
+}
+
+type servicesKey struct{}
+
+// NewServices creates a new empty Services
+func NewServices() *Services {
+	return &Services{services: map[reflect.Type]*service{}}
+}
+
+// Services returns itself, so Services is a ServiceProvider
+func (s *Services) Services() *Services {
+	return s
+}
+
+// Provide registers the service of type T, replacing the previous one.
+// T is usually an interface, to be replaced by a fake in tests.
+func Provide[T any](s *Services, v T) {
+	sv := &service{value: v}
+	sv.once.Do(func() {})
+	s.set(reflect.TypeFor[T](), sv)
+}
+
+// ProvideFunc registers the service of type T created by factory when it is resolved for the first time.
+// An error returned by factory is returned by every call to Resolve.
+func ProvideFunc[T any](s *Services, factory func() (T, error)) {
+	s.set(reflect.TypeFor[T](), &service{factory: func() (any, error) {
+		return factory()
+	}})
+}
+
+// Resolve returns the service of type T.
+// It returns ErrServiceNotFound if the service was not registered.
+func Resolve[T any](p ServiceProvider) (T, error) {
+	var zero T
+	var s *Services
+	if p != nil {
+		s = p.Services()
+	}
+	t := reflect.TypeFor[T]()
+	if s == nil {
+		return zero, fmt.Errorf("%w: %s", ErrServiceNotFound, t)
+	}
+	s.mu.RLock()
+	sv, ok := s.services[t]
+	s.mu.RUnlock()
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrServiceNotFound, t)
+	}
+	sv.once.Do(func() {
+		sv.value, sv.err = sv.factory()
+	})
+	if sv.err != nil {
+		return zero, sv.err
+	}
+	// the value may be nil if the service is a nil interface
+	v, _ := sv.value.(T)
+	return v, nil
+}
+
+// MustResolve returns the service of type T and panics if it cannot be resolved.
+// It is designed to be used with services registered at startup.
+func MustResolve[T any](p ServiceProvider) T {
+	v, err := Resolve[T](p)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// WithServices returns a copy of ctx containing the Services (already done by gokord for event handlers)
+func WithServices(ctx context.Context, s *Services) context.Context {
+	return context.WithValue(ctx, servicesKey{}, s)
+}
+
+// ServicesFrom returns the Services contained in ctx (nil if there is none).
+// Use it with Resolve.
+func ServicesFrom(ctx context.Context) *Services {
+	s, _ := ctx.Value(servicesKey{}).(*Services)
+	return s
+}
+
+func (s *Services) set(t reflect.Type, sv *service) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.services[t] = sv
+}
@@ -52,7 +52,9 @@ func (b *Bot) generalHandler(s bot.Session, i *event.InteractionCreate, _ cmd.Op
 	}
 	for _, sub := range c.GetSubs() {
 		if subInfo.Name == sub.GetName() {
-			sub.GetHandler()(s, i, cmd.GenerateOptionMapForSubcommand(i), resp)
+			name := c.GetName() + " " + sub.GetName()
+			h := b.withGuards(name, sub.GetGuards(), b.withCooldown(name, sub.GetCooldown(), sub.GetHandler()))
+			h(s, i, cmd.GenerateOptionMapForSubcommand(i), resp)
 			return
 		}
 	}
@@ -6,16 +6,19 @@ import (
 	"log/slog"
 	"os"
 
-	"github.com/anhgelus/gokord/cmd"
 	"github.com/pelletier/go-toml/v2"
 	"gorm.io/gorm"
 )
 
 var (
 	// BaseCfg is the main BaseConfig used by the bot
+	//
+	// Deprecated: use Bot.Config (set by Bot.SetupConfigs).
 	BaseCfg BaseConfig
 
 	// UseRedis is true if the bot will use redis
+	//
+	// Deprecated: redis is used by a Bot if BaseConfig.GetRedisCredentials does not return nil.
 	UseRedis = true
 
 	ErrImpossibleToConnectDB         = errors.New("impossible to connect to the database")
@@ -32,9 +35,11 @@ type BaseConfig interface {
 	IsDebug() bool
 	// GetAuthor returns the author (or the owner) of the bot
 	GetAuthor() string
+	// GetDevGuilds returns the IDs of the guilds where commands are registered if the bot is in debug mode
+	GetDevGuilds() []string
 	// GetRedisCredentials returns the RedisCredentials used by the bot.
 	//
-	// Must return nil if gokord.UseRedis is false
+	// Must return nil if the bot does not use redis
 	GetRedisCredentials() *RedisCredentials
 	// GetSQLCredentials returns the SQLCredentials used by the bot
 	GetSQLCredentials() SQLCredentials
@@ -76,11 +81,11 @@ type ConfigInfo struct {
 	DefaultValues func()      // DefaultValues is called to set up the default values of the config
 }
 
-func setupBaseConfig() error {
-	return LoadConfig(&BaseCfg, "config", BaseCfg.SetDefaultValues, func(_ interface{}) ([]byte, error) {
-		return BaseCfg.Marshal()
+func setupBaseConfig(cfg BaseConfig, name string) error {
+	return LoadConfig(cfg, name, cfg.SetDefaultValues, func(_ interface{}) ([]byte, error) {
+		return cfg.Marshal()
 	}, func(data []byte, _ interface{}) error {
-		return BaseCfg.Unmarshal(data)
+		return cfg.Unmarshal(data)
 	})
 }
 
@@ -115,44 +120,84 @@ func LoadConfig(cfg interface{}, name string, defaultValues func(), marshal func
 // SetupConfigs with the given configs (+ base config which is available at BaseCfg)
 //
 // customBaseConfig is the new type of BaseCfg
+//
+// Deprecated: use Bot.SetupConfigs, this function sets global variables shared by every Bot.
 func SetupConfigs(customBaseConfig BaseConfig, cfgInfo []*ConfigInfo) error {
-	var err error
 	BaseCfg = customBaseConfig
-	err = setupBaseConfig()
+	db, err := setupConfigs(customBaseConfig, "config", cfgInfo, UseRedis)
 	if err != nil {
 		return err
 	}
-
+	DB = db
 	Debug = BaseCfg.IsDebug()
-	cmd.Author = BaseCfg.GetAuthor()
 	if Debug {
 		slog.SetLogLoggerLevel(slog.LevelDebug)
 	}
+	return nil
+}
 
-	for _, cfg := range cfgInfo {
-		err = LoadConfig(cfg.Cfg, cfg.Name, cfg.DefaultValues, toml.Marshal, toml.Unmarshal)
-		if err != nil {
-			return err
-		}
+// SetupConfigs of the Bot with the given configs (+ base config which is available at Bot.Config, and configs of the
+// modules added).
+// It connects to the database (available at Bot.DB) and checks the connection to redis.
+//
+// The base config is stored in the file named Bot.ConfigName ("config" if empty), so each Bot of the same process
+// must have a different name.
+func (b *Bot) SetupConfigs(customBaseConfig BaseConfig, cfgInfo []*ConfigInfo) error {
+	name := b.ConfigName
+	if name == "" {
+		name = "config"
 	}
+	db, err := setupConfigs(customBaseConfig, name, append(cfgInfo, b.moduleConfigs()...), true)
+	if err != nil {
+		return err
+	}
+	b.Config = customBaseConfig
+	b.DB = db
+	b.ownedDB = db
+	b.Debug = customBaseConfig.IsDebug()
+	return nil
+}
 
-	DB, err = BaseCfg.GetSQLCredentials().Connect()
+// setupConfigs loads the configs and connects to the database.
+// The connection to redis is checked if useRedis is true and if the config contains RedisCredentials.
+func setupConfigs(cfg BaseConfig, name string, cfgInfo []*ConfigInfo, useRedis bool) (*gorm.DB, error) {
+	err := setupBaseConfig(cfg, name)
 	if err != nil {
-		return errors.Join(ErrImpossibleToConnectDB, err)
+		return nil, err
+	}
+
+	if err = loadConfigs(cfgInfo); err != nil {
+		return nil, err
 	}
 
-	err = DB.AutoMigrate(&BotData{})
+	db, err := cfg.GetSQLCredentials().Connect()
 	if err != nil {
-		return errors.Join(ErrMigratingGokordInternalModels, err)
+		return nil, errors.Join(ErrImpossibleToConnectDB, err)
 	}
 
-	if !UseRedis {
-		return nil
+	err = db.AutoMigrate(&BotData{}, &GuildCommand{})
+	if err != nil {
+		return nil, errors.Join(ErrMigratingGokordInternalModels, err)
+	}
+
+	if !useRedis || cfg.GetRedisCredentials() == nil {
+		return db, nil
 	}
-	c, err := BaseCfg.GetRedisCredentials().Connect()
+	c, err := cfg.GetRedisCredentials().Connect()
 	if err != nil {
-		return errors.Join(ErrImpossibleToConnectRedis, err)
+		return nil, errors.Join(ErrImpossibleToConnectRedis, err)
 	}
 	_ = c.Close()
+	return db, nil
+}
+
+// loadConfigs with toml
+func loadConfigs(cfgInfo []*ConfigInfo) error {
+	for _, c := range cfgInfo {
+		err := LoadConfig(c.Cfg, c.Name, c.DefaultValues, toml.Marshal, toml.Unmarshal)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,212 @@
+package gokord
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/event"
+	"github.com/redis/go-redis/v9"
+)
+
+// CooldownMessages are sent when a command is on cooldown, indexed by the locale of the user.
+// %s is replaced by the time when the command will be available (relative timestamp, localized by Discord).
+//
+// The message linked with "" is used if the locale is not present.
+var CooldownMessages = map[string]string{
+	"":   "This command is on cooldown, try again %s.",
+	"fr": "Cette commande est en cooldown, réessaye %s.",
+}
+
+// CooldownStore stores uses of commands with a cmd.Cooldown
+type CooldownStore interface {
+	// Take a use of the cmd.Cooldown linked with the key.
+	// It returns 0 if the use is allowed, or the duration to wait before the next allowed use.
+	Take(ctx context.Context, key string, c *cmd.Cooldown) (time.Duration, error)
+}
+
+// memoryCooldownStore is a CooldownStore in memory (not shared between instances)
+type memoryCooldownStore struct {
+	mu      sync.Mutex
+	entries map[string]*cooldownEntry
+}
+
+// cooldownEntry is the state of a cmd.Cooldown in memoryCooldownStore
+type cooldownEntry struct {
+	// value is the number of uses for cmd.FixedWindow, and the number of tokens for cmd.TokenBucket
+	value float64
+	// at is the start of the window for cmd.FixedWindow, and the last refill for cmd.TokenBucket
+	at     time.Time
+	period time.Duration
+}
+
+// redisCooldownStore is a CooldownStore using redis (shared between instances)
+type redisCooldownStore struct {
+	client *redis.Client
+}
+
+// NewMemoryCooldownStore creates a CooldownStore in memory
+func NewMemoryCooldownStore() CooldownStore {
+	return &memoryCooldownStore{entries: make(map[string]*cooldownEntry)}
+}
+
+// NewRedisCooldownStore creates a CooldownStore using the given redis client
+func NewRedisCooldownStore(client *redis.Client) CooldownStore {
+	return &redisCooldownStore{client: client}
+}
+
+func (m *memoryCooldownStore) Take(_ context.Context, key string, c *cmd.Cooldown) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.clean(now)
+	e, ok := m.entries[key]
+	if c.Strategy == cmd.TokenBucket {
+		if !ok {
+			e = &cooldownEntry{value: float64(c.Limit), at: now, period: c.Period}
+			m.entries[key] = e
+		}
+		rate := float64(c.Limit) / float64(c.Period)
+		e.value = math.Min(float64(c.Limit), e.value+float64(now.Sub(e.at))*rate)
+		e.at = now
+		if e.value < 1 {
+			return time.Duration((1 - e.value) / rate), nil
+		}
+		e.value--
+		return 0, nil
+	}
+	if !ok || now.Sub(e.at) >= c.Period {
+		e = &cooldownEntry{at: now, period: c.Period}
+		m.entries[key] = e
+	}
+	if int(e.value) >= c.Limit {
+		return e.at.Add(c.Period).Sub(now), nil
+	}
+	e.value++
+	return 0, nil
+}
+
+// clean removes entries that are not used anymore
+func (m *memoryCooldownStore) clean(now time.Time) {
+	for k, e := range m.entries {
+		if now.Sub(e.at) > e.period {
+			delete(m.entries, k)
+		}
+	}
+}
+
+var (
+	// KEYS[1] = key, ARGV[1] = limit, ARGV[2] = period in ms
+	// returns 0 or the remaining time in ms
+	fixedWindowScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if n > tonumber(ARGV[1]) then
+	return math.max(redis.call("PTTL", KEYS[1]), 1)
+end
+return 0
+`)
+	// KEYS[1] = key, ARGV[1] = limit, ARGV[2] = period in ms
+	// returns 0 or the remaining time in ms
+	tokenBucketScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local state = redis.call("HMGET", KEYS[1], "tokens", "at")
+local tokens = tonumber(state[1]) or limit
+local at = tonumber(state[2]) or now
+local rate = limit / period
+tokens = math.min(limit, tokens + (now - at) * rate)
+local wait = 0
+if tokens < 1 then
+	wait = math.ceil((1 - tokens) / rate)
+else
+	tokens = tokens - 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "at", now)
+redis.call("PEXPIRE", KEYS[1], period)
+return wait
+`)
+)
+
+func (r *redisCooldownStore) Take(ctx context.Context, key string, c *cmd.Cooldown) (time.Duration, error) {
+	script := fixedWindowScript
+	if c.Strategy == cmd.TokenBucket {
+		script = tokenBucketScript
+	}
+	ms, err := script.Run(ctx, r.client, []string{key}, c.Limit, c.Period.Milliseconds()).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// cooldownStore returns the CooldownStore of the Bot.
+// If Bot.CooldownStore is nil, redis is used if the Bot uses it, else the store is in memory.
+func (b *Bot) cooldownStore() CooldownStore {
+	b.cooldownOnce.Do(func() {
+		if b.CooldownStore != nil {
+			return
+		}
+		if b.useRedis() {
+			c, err := b.connectRedis()
+			if err == nil {
+				b.CooldownStore = NewRedisCooldownStore(c)
+				return
+			}
+			b.Logger.Error("connecting to redis for cooldowns, using memory", "error", err)
+		}
+		b.CooldownStore = NewMemoryCooldownStore()
+	})
+	return b.CooldownStore
+}
+
+// withCooldown returns a cmd.CommandHandler enforcing the cmd.Cooldown before calling the handler.
+//
+// name is the full name of the command (including subcommands)
+func (b *Bot) withCooldown(name string, c *cmd.Cooldown, handler cmd.CommandHandler) cmd.CommandHandler {
+	if c == nil || c.Limit <= 0 || c.Period <= 0 {
+		return handler
+	}
+	return func(s bot.Session, i *event.InteractionCreate, optMap cmd.OptionMap, resp *cmd.ResponseBuilder) {
+		wait, err := b.cooldownStore().Take(resp.Context(), cooldownKey(name, c, i), c)
+		if err != nil {
+			// a broken store must not break commands
+			b.Logger.Error("taking cooldown", "error", err, "command", name)
+		} else if wait > 0 {
+			at := fmt.Sprintf("<t:%d:R>", time.Now().Add(wait).Add(time.Second).Unix())
+			err = resp.IsEphemeral().SetMessage(fmt.Sprintf(cmd.LocalizedMessage(CooldownMessages, string(i.Locale)), at)).Send()
+			if err != nil {
+				b.Logger.Error("sending cooldown message", "error", err, "command", name)
+			}
+			return
+		}
+		handler(s, i, optMap, resp)
+	}
+}
+
+func cooldownKey(name string, c *cmd.Cooldown, i *event.InteractionCreate) string {
+	var id string
+	switch c.Scope {
+	case cmd.CooldownGuild:
+		id = i.GuildID
+		if id == "" {
+			// no guild in DMs
+			id = "dm:" + cmd.InteractionUserID(i)
+		}
+	case cmd.CooldownChannel:
+		id = i.ChannelID
+	case cmd.CooldownGlobal:
+		id = "global"
+	default:
+		id = cmd.InteractionUserID(i)
+	}
+	return fmt.Sprintf("gokord:cooldown:%s:%d:%s", name, c.Scope, id)
+}
@@ -0,0 +1,49 @@
+package gokord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhgelus/gokord/cmd"
+)
+
+func TestMemoryCooldownStore(t *testing.T) {
+	tests := []struct {
+		name     string
+		cooldown *cmd.Cooldown
+		uses     int
+		// wait expected after uses, with a margin for the time spent by the test
+		wait time.Duration
+	}{
+		{"fixed window under limit", cmd.NewCooldown(cmd.CooldownUser, 3, time.Hour), 3, 0},
+		{"fixed window limit reached", cmd.NewCooldown(cmd.CooldownUser, 2, time.Hour), 3, time.Hour},
+		{"fixed window single use", cmd.NewCooldown(cmd.CooldownUser, 1, time.Minute), 2, time.Minute},
+		{"token bucket under limit", cmd.NewCooldown(cmd.CooldownUser, 3, time.Hour).WithTokenBucket(), 3, 0},
+		{"token bucket empty", cmd.NewCooldown(cmd.CooldownUser, 2, time.Hour).WithTokenBucket(), 3, 30 * time.Minute},
+		{"token bucket single token", cmd.NewCooldown(cmd.CooldownUser, 1, time.Hour).WithTokenBucket(), 2, time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryCooldownStore()
+			var wait time.Duration
+			for i := range tt.uses {
+				w, err := store.Take(context.Background(), "key", tt.cooldown)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if i < tt.uses-1 && w != 0 {
+					t.Fatalf("use %d: got wait %s, want 0", i+1, w)
+				}
+				wait = w
+			}
+			if wait > tt.wait || wait < tt.wait-time.Second {
+				t.Errorf("got wait %s, want %s", wait, tt.wait)
+			}
+			// other keys are not affected
+			if w, err := store.Take(context.Background(), "other", tt.cooldown); err != nil || w != 0 {
+				t.Errorf("other key: got wait %s (error: %v), want 0", w, err)
+			}
+		})
+	}
+}
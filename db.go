@@ -5,6 +5,8 @@ import (
 )
 
 // DB used
+//
+// Deprecated: use Bot.DB (set by Bot.SetupConfigs).
 var DB *gorm.DB
 
 // DataBase is an interface with basic methods to load and save data
@@ -17,12 +19,26 @@ type BotData struct {
 	gorm.Model
 	Version string `gorm:"version"`
 	Name    string `gorm:"name"`
+	db      *gorm.DB
+}
+
+// botData returns the BotData of the Bot (not loaded)
+func (b *Bot) botData() *BotData {
+	return &BotData{Name: b.Name, db: b.DB}
+}
+
+// database returns the database used by the BotData (DB if it was not created by a Bot)
+func (b *BotData) database() *gorm.DB {
+	if b.db == nil {
+		return DB
+	}
+	return b.db
 }
 
 func (b *BotData) Load() error {
-	return DB.FirstOrCreate(b).Error
+	return b.database().FirstOrCreate(b).Error
 }
 
 func (b *BotData) Save() error {
-	return DB.Save(b).Error
+	return b.database().Save(b).Error
 }
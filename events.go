@@ -0,0 +1,247 @@
+package gokord
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/channel"
+	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/user"
+)
+
+// EventHandler handles an event of type E
+type EventHandler[E any] func(ctx context.Context, s bot.Session, e *E)
+
+// EventFunc is an EventHandler receiving any event, used by EventMiddleware
+type EventFunc func(ctx context.Context, s bot.Session, e any)
+
+// EventMiddleware wraps every EventHandler registered with OnEvent (or with a typed helper like Bot.OnMessageCreate),
+// like CommandMiddleware for commands.
+// It must call next to handle the event.
+type EventMiddleware func(next EventFunc) EventFunc
+
+// EventFilter returns true if the event must be handled
+type EventFilter func(s bot.Session, e any) bool
+
+// OnEvent registers the EventHandler called for each event of type E passing every EventFilter.
+// It is applied when the Bot is opened.
+//
+// The handler is tracked like commands (Bot.Close waits for it), a panic is recovered and logged, and the
+// EventMiddleware added with Bot.UseEventMiddleware are applied.
+func OnEvent[E any](b *Bot, h EventHandler[E], filters ...EventFilter) {
+	name := "event " + reflect.TypeFor[E]().Name()
+	b.AddHandler(func(ctx context.Context, s bot.Session, e *E) {
+		for _, f := range filters {
+			if !f(s, e) {
+				return
+			}
+		}
+		done, ok := b.track(name)
+		if !ok {
+			return
+		}
+		defer done()
+		defer b.recoverHandler(name)
+		next := func(ctx context.Context, s bot.Session, e any) {
+			h(ctx, s, e.(*E))
+		}
+		for _, m := range slices.Backward(b.eventMiddlewares) {
+			next = m(next)
+		}
+		next(ctx, s, e)
+	})
+}
+
+// UseEventMiddleware adds EventMiddleware applied to every EventHandler, in the order of their addition
+func (b *Bot) UseEventMiddleware(m ...EventMiddleware) {
+	b.eventMiddlewares = append(b.eventMiddlewares, m...)
+}
+
+// OnMessageCreate registers the EventHandler called when a message is sent (see OnEvent)
+func (b *Bot) OnMessageCreate(h EventHandler[event.MessageCreate], filters ...EventFilter) {
+	OnEvent(b, h, filters...)
+}
+
+// OnMessageUpdate registers the EventHandler called when a message is edited (see OnEvent)
+func (b *Bot) OnMessageUpdate(h EventHandler[event.MessageUpdate], filters ...EventFilter) {
+	OnEvent(b, h, filters...)
+}
+
+// OnMessageDelete registers the EventHandler called when a message is deleted (see OnEvent)
+func (b *Bot) OnMessageDelete(h EventHandler[event.MessageDelete], filters ...EventFilter) {
+	OnEvent(b, h, filters...)
+}
+
+// OnGuildMemberAdd registers the EventHandler called when a member joins a guild (see OnEvent)
+func (b *Bot) OnGuildMemberAdd(h EventHandler[event.GuildMemberAdd], filters ...EventFilter) {
+	OnEvent(b, h, filters...)
+}
+
+// OnGuildMemberRemove registers the EventHandler called when a member leaves a guild (see OnEvent)
+func (b *Bot) OnGuildMemberRemove(h EventHandler[event.GuildMemberRemove], filters ...EventFilter) {
+	OnEvent(b, h, filters...)
+}
+
+// OnReactionAdd registers the EventHandler called when a reaction is added to a message (see OnEvent)
+func (b *Bot) OnReactionAdd(h EventHandler[event.MessageReactionAdd], filters ...EventFilter) {
+	OnEvent(b, h, filters...)
+}
+
+// OnReactionRemove registers the EventHandler called when a reaction is removed from a message (see OnEvent)
+func (b *Bot) OnReactionRemove(h EventHandler[event.MessageReactionRemove], filters ...EventFilter) {
+	OnEvent(b, h, filters...)
+}
+
+// recoverHandler recovers a panic of the handler and logs it.
+// It must be deferred.
+func (b *Bot) recoverHandler(name string) {
+	if r := recover(); r != nil {
+		b.Logger.Error("handler panicked", "handler", name, "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+	}
+}
+
+// IgnoreBots ignores events sent by bots (including the Bot)
+func IgnoreBots() EventFilter {
+	return func(s bot.Session, e any) bool {
+		u := eventUser(e)
+		if u == nil {
+			// reactions only contain the ID of the user when the member is unknown
+			me := s.SessionState().User()
+			return me == nil || eventField(reflect.ValueOf(e), "UserID") != me.ID
+		}
+		return !u.Bot
+	}
+}
+
+// InGuild ignores events outside guilds
+func InGuild() EventFilter {
+	return func(_ bot.Session, e any) bool {
+		return eventField(reflect.ValueOf(e), "GuildID") != ""
+	}
+}
+
+// InGuilds only handles events of the given guilds
+func InGuilds(guildIDs ...string) EventFilter {
+	return func(_ bot.Session, e any) bool {
+		return slices.Contains(guildIDs, eventField(reflect.ValueOf(e), "GuildID"))
+	}
+}
+
+// InChannels only handles events of the given channels
+func InChannels(channelIDs ...string) EventFilter {
+	return func(_ bot.Session, e any) bool {
+		return slices.Contains(channelIDs, eventField(reflect.ValueOf(e), "ChannelID"))
+	}
+}
+
+// WithPrefix only handles messages starting with the prefix
+func WithPrefix(prefix string) EventFilter {
+	return func(_ bot.Session, e any) bool {
+		m := eventMessage(e)
+		return m != nil && strings.HasPrefix(m.Content, prefix)
+	}
+}
+
+// MatchContent only handles messages matching the regex
+func MatchContent(re *regexp.Regexp) EventFilter {
+	return func(_ bot.Session, e any) bool {
+		m := eventMessage(e)
+		return m != nil && re.MatchString(m.Content)
+	}
+}
+
+// Not inverts the EventFilter
+func Not(f EventFilter) EventFilter {
+	return func(s bot.Session, e any) bool {
+		return !f(s, e)
+	}
+}
+
+// AnyOf handles events passing at least one EventFilter
+func AnyOf(filters ...EventFilter) EventFilter {
+	return func(s bot.Session, e any) bool {
+		return slices.ContainsFunc(filters, func(f EventFilter) bool { return f(s, e) })
+	}
+}
+
+// FilterFunc creates an EventFilter for events of type E.
+// Events of another type are ignored.
+func FilterFunc[E any](fn func(s bot.Session, e *E) bool) EventFilter {
+	return func(s bot.Session, e any) bool {
+		ev, ok := e.(*E)
+		return ok && fn(s, ev)
+	}
+}
+
+// eventMessage returns the message of the event (nil if there is none)
+func eventMessage(e any) *channel.Message {
+	switch ev := e.(type) {
+	case *event.MessageCreate:
+		return ev.Message
+	case *event.MessageUpdate:
+		return ev.Message
+	case *event.MessageDelete:
+		return ev.Message
+	}
+	return nil
+}
+
+// eventUser returns the user who triggered the event (nil if it is unknown)
+func eventUser(e any) *user.User {
+	if m := eventMessage(e); m != nil {
+		return m.Author
+	}
+	var m *user.Member
+	switch ev := e.(type) {
+	case *event.GuildMemberAdd:
+		m = ev.Member
+	case *event.GuildMemberRemove:
+		m = ev.Member
+	case *event.MessageReactionAdd:
+		m = ev.Member
+	}
+	if m != nil && m.User != nil {
+		return m.User
+	}
+	// e.g., reactions in DMs do not have a member
+	u, _ := eventValue(reflect.ValueOf(e), "User").(*user.User)
+	return u
+}
+
+// eventField returns the value of the string field of the event (empty if there is none)
+func eventField(e reflect.Value, name string) string {
+	v := reflect.ValueOf(eventValue(e, name))
+	if v.Kind() != reflect.String {
+		return ""
+	}
+	return v.String()
+}
+
+// eventValue returns the value of the field of the event (nil if there is none)
+func eventValue(e reflect.Value, name string) any {
+	for e.Kind() == reflect.Pointer || e.Kind() == reflect.Interface {
+		if e.IsNil() {
+			return nil
+		}
+		e = e.Elem()
+	}
+	if e.Kind() != reflect.Struct {
+		return nil
+	}
+	f, ok := e.Type().FieldByName(name)
+	if !ok || !f.IsExported() {
+		return nil
+	}
+	// a nil embedded struct returns an error
+	fv, err := e.FieldByIndexErr(f.Index)
+	if err != nil {
+		return nil
+	}
+	return fv.Interface()
+}
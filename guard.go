@@ -0,0 +1,40 @@
+package gokord
+
+import (
+	"errors"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/event"
+)
+
+// withGuards returns a cmd.CommandHandler checking every cmd.Guard before calling the handler.
+//
+// name is the full name of the command (including subcommands)
+func (b *Bot) withGuards(name string, guards []cmd.Guard, handler cmd.CommandHandler) cmd.CommandHandler {
+	if len(guards) == 0 {
+		return handler
+	}
+	return func(s bot.Session, i *event.InteractionCreate, optMap cmd.OptionMap, resp *cmd.ResponseBuilder) {
+		ctx := &cmd.GuardContext{Context: resp.Context(), Session: s, Interaction: i, IsOwner: b.IsOwner}
+		for _, g := range guards {
+			err := g(ctx)
+			if err == nil {
+				continue
+			}
+			var gErr *cmd.GuardError
+			if !errors.As(err, &gErr) {
+				b.Logger.Error("checking guard", "error", err, "command", name)
+				gErr = &cmd.GuardError{Denial: cmd.DeniedInternal}
+			} else {
+				b.Logger.Debug("command denied", "command", name, "denial", gErr.Denial, "user", cmd.InteractionUserID(i))
+			}
+			err = resp.IsEphemeral().SetMessage(gErr.Localized(string(i.Locale))).Send()
+			if err != nil {
+				b.Logger.Error("sending denial message", "error", err, "command", name)
+			}
+			return
+		}
+		handler(s, i, optMap, resp)
+	}
+}
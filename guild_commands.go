@@ -0,0 +1,127 @@
+package gokord
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/interaction"
+	"gorm.io/gorm/clause"
+)
+
+var ErrCommandNotGuildScoped = errors.New("command does not exist or is not guild scoped")
+
+// GuildCommand is a guild scoped command (see cmd.CommandBuilder.IsGuildScoped) enabled in a guild
+type GuildCommand struct {
+	GuildID string `gorm:"primaryKey"`
+	Command string `gorm:"primaryKey"`
+}
+
+// GuildCommands returns the name of the guild scoped commands enabled in the guild
+func (b *Bot) GuildCommands(guildID string) ([]string, error) {
+	var names []string
+	err := b.DB.Model(&GuildCommand{}).Where("guild_id = ?", guildID).Pluck("command", &names).Error
+	return names, err
+}
+
+// EnableGuildCommands in the guild and registers them.
+// Commands must be guild scoped.
+func (b *Bot) EnableGuildCommands(s bot.Session, guildID string, names ...string) error {
+	rows := make([]*GuildCommand, len(names))
+	for i, n := range names {
+		if !slices.ContainsFunc(b.Commands, func(c cmd.CommandBuilder) bool {
+			return c.GetName() == n && c.GuildScoped()
+		}) {
+			return errors.Join(ErrCommandNotGuildScoped, errors.New(n))
+		}
+		rows[i] = &GuildCommand{GuildID: guildID, Command: n}
+	}
+	if len(rows) > 0 {
+		if err := b.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(rows).Error; err != nil {
+			return err
+		}
+	}
+	return b.SyncGuildCommands(s, guildID)
+}
+
+// DisableGuildCommands in the guild and unregisters them
+func (b *Bot) DisableGuildCommands(s bot.Session, guildID string, names ...string) error {
+	err := b.DB.Where("guild_id = ? AND command IN ?", guildID, names).Delete(&GuildCommand{}).Error
+	if err != nil {
+		return err
+	}
+	return b.SyncGuildCommands(s, guildID)
+}
+
+// SyncGuildCommands registers the guild scoped commands enabled in the guild and unregisters the others.
+//
+// It does nothing if Bot.Debug is true, because every command is already registered in the development guilds.
+func (b *Bot) SyncGuildCommands(s bot.Session, guildID string) error {
+	if b.Debug {
+		b.Logger.Debug("guild commands not synced in debug", "guild", guildID)
+		return nil
+	}
+	enabled, err := b.GuildCommands(guildID)
+	if err != nil {
+		return err
+	}
+	cmds := make([]*interaction.Command, 0, len(enabled))
+	for _, c := range b.Commands {
+		if c.GuildScoped() && slices.Contains(enabled, c.GetName()) {
+			cmds = append(cmds, c.ApplicationCommand())
+		}
+	}
+	_, err = s.InteractionAPI().CommandBulkOverwrite(b.applicationID(s), guildID, cmds)
+	if err == nil {
+		b.Logger.Debug("guild commands synced", "guild", guildID, "commands", len(cmds))
+	}
+	return err
+}
+
+// syncJoinedGuildCommands registers the guild scoped commands enabled in the guild joined by the Bot.
+// It is not called when a known guild becomes available, because its commands are already registered.
+func (b *Bot) syncJoinedGuildCommands(s bot.Session, guildID string) {
+	if b.DB == nil || b.Debug || !slices.ContainsFunc(b.Commands, cmd.CommandBuilder.GuildScoped) {
+		return
+	}
+	var n int64
+	if err := b.DB.Model(&GuildCommand{}).Where("guild_id = ?", guildID).Count(&n).Error; err != nil {
+		b.Logger.Error("counting guild commands", "error", err, "guild", guildID)
+		return
+	}
+	// nothing to register (DisableGuildCommands already unregistered the commands)
+	if n == 0 {
+		return
+	}
+	if err := b.SyncGuildCommands(s, guildID); err != nil {
+		b.Logger.Error("syncing guild commands", "error", err, "guild", guildID)
+	}
+}
+
+// syncUpdatedGuildCommands syncs the guilds which enabled a guild scoped command added or updated by the Innovation
+func (b *Bot) syncUpdatedGuildCommands(s bot.Session, update *InnovationCommands) {
+	if b.DB == nil {
+		return
+	}
+	var names []string
+	for _, n := range slices.Concat(update.Added, update.Updated) {
+		if c := b.command(n); c != nil && c.GuildScoped() {
+			names = append(names, n)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	var guilds []string
+	err := b.DB.Model(&GuildCommand{}).Where("command IN ?", names).Distinct().Pluck("guild_id", &guilds).Error
+	if err != nil {
+		b.Logger.Error("getting guilds with updated commands", "error", err)
+		return
+	}
+	for _, guildID := range guilds {
+		if err = b.SyncGuildCommands(s, guildID); err != nil {
+			b.Logger.Error("syncing guild commands", "error", err, "guild", guildID)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+package gokord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	// guildSettingsCacheTTL is the duration of the redis cache of GuildSettings
+	guildSettingsCacheTTL = 30 * time.Minute
+	// settingsResetAll is the value of the option of /config reset resetting every setting
+	settingsResetAll = "all"
+	// maxSettings is the maximum number of settings (number of options of a command)
+	maxSettings = 25
+	// maxChoices is the maximum number of choices of an option
+	maxChoices = 25
+)
+
+var (
+	ErrSettingsNotStruct      = errors.New("settings must be a struct")
+	ErrUnsupportedSettingType = errors.New("unsupported setting type")
+	ErrInvalidSettingName     = errors.New("invalid setting name")
+	ErrTooManySettings        = errors.New("too many settings (25 max)")
+	ErrSettingNotFound        = errors.New("setting not found")
+	ErrInvalidSettingValue    = errors.New("invalid setting value")
+	ErrGuildSettingsNotLoaded = errors.New("guild settings are not loaded (Bot.DB is nil)")
+)
+
+var (
+	settingNameRegex = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+	// settingOptionTypes are the values of the tag `type` of a string setting
+	settingOptionTypes = map[string]types.CommandOption{
+		"channel":     types.CommandOptionChannel,
+		"role":        types.CommandOptionRole,
+		"user":        types.CommandOptionUser,
+		"mentionable": types.CommandOptionMentionable,
+	}
+)
+
+// GuildSettingsData is the model storing GuildSettings in the database
+type GuildSettingsData struct {
+	Name      string `gorm:"primaryKey"`
+	GuildID   string `gorm:"primaryKey"`
+	Data      string
+	UpdatedAt time.Time
+}
+
+// GuildSettings stores settings of type T for each guild.
+// Settings are stored in the database and cached in redis if the Bot uses it (in memory otherwise).
+//
+// Each exported field of T with the tag `setting` is a setting editable with the command returned by
+// GuildSettings.Command:
+//
+//	type Settings struct {
+//		LogChannel string `setting:"log_channel" desc:"Channel where logs are sent" type:"channel"`
+//		Language   string `setting:"language" desc:"Language of the bot" choices:"en,fr"`
+//		MaxWarns   int    `setting:"max_warns" desc:"Warns before a ban" min:"1" max:"10"`
+//	}
+//
+// Supported tags are:
+//   - setting: name of the setting (required, lowercase, 32 characters max);
+//   - desc: description of the setting (the name is used if empty);
+//   - type: "channel", "role", "user" or "mentionable" for a string containing an ID;
+//   - choices: allowed values of a string separated by commas (shown as choices by Discord if there are 25 or less);
+//   - min and max: bounds of a number, or of the length of a string.
+//
+// Supported types are string, bool, integers and floats.
+type GuildSettings[T any] struct {
+	bot      *Bot
+	name     string
+	defaults func() *T
+	fields   []*settingField
+	// Validate is called before saving settings modified by the command.
+	// The error is sent to the user.
+	Validate func(guildID string, settings *T) error
+	// OnChange is called after settings are modified by the command
+	OnChange func(s bot.Session, guildID string, settings *T)
+
+	once   sync.Once
+	err    error
+	db     *gorm.DB
+	redis  *redis.Client
+	mu     sync.RWMutex
+	memory map[string][]byte
+}
+
+// settingField is a setting of GuildSettings
+type settingField struct {
+	index    []int
+	name     string
+	desc     string
+	kind     reflect.Kind
+	optType  types.CommandOption
+	choices  []string
+	min, max *float64
+}
+
+// NewGuildSettings creates GuildSettings of the Bot named name (must be unique in the Bot) using the struct returned by
+// defaults as default settings
+func NewGuildSettings[T any](b *Bot, name string, defaults func() *T) (*GuildSettings[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, ErrSettingsNotStruct
+	}
+	g := &GuildSettings[T]{bot: b, name: name, defaults: defaults, memory: make(map[string][]byte)}
+	for _, f := range reflect.VisibleFields(t) {
+		n, ok := f.Tag.Lookup("setting")
+		if !ok || !f.IsExported() {
+			continue
+		}
+		field, err := parseSettingField(f, n)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(g.fields, func(o *settingField) bool { return o.name == field.name }) {
+			return nil, fmt.Errorf("%w: %s is duplicated", ErrInvalidSettingName, n)
+		}
+		g.fields = append(g.fields, field)
+	}
+	if len(g.fields) > maxSettings {
+		return nil, ErrTooManySettings
+	}
+	return g, nil
+}
+
+func parseSettingField(f reflect.StructField, name string) (*settingField, error) {
+	if !settingNameRegex.MatchString(name) || name == settingsResetAll {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSettingName, name)
+	}
+	field := &settingField{index: f.Index, name: name, desc: f.Tag.Get("desc"), kind: f.Type.Kind()}
+	if field.desc == "" {
+		field.desc = name
+	}
+	switch field.kind {
+	case reflect.String:
+		field.optType = types.CommandOptionString
+		if t := f.Tag.Get("type"); t != "" {
+			var ok bool
+			if field.optType, ok = settingOptionTypes[t]; !ok {
+				return nil, fmt.Errorf("%w: %s for %s", ErrUnsupportedSettingType, t, name)
+			}
+		}
+	case reflect.Bool:
+		field.optType = types.CommandOptionBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		field.optType = types.CommandOptionInteger
+	case reflect.Float32, reflect.Float64:
+		field.optType = types.CommandOptionNumber
+	default:
+		return nil, fmt.Errorf("%w: %s for %s", ErrUnsupportedSettingType, f.Type, name)
+	}
+	if c := f.Tag.Get("choices"); c != "" {
+		field.choices = strings.Split(c, ",")
+	}
+	for tag, p := range map[string]**float64{"min": &field.min, "max": &field.max} {
+		v, ok := f.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s of %s: %w", ErrInvalidSettingValue, tag, name, err)
+		}
+		*p = &n
+	}
+	return field, nil
+}
+
+// init connects GuildSettings to the database and to redis
+func (g *GuildSettings[T]) init() error {
+	g.once.Do(func() {
+		if g.bot.DB == nil {
+			g.err = ErrGuildSettingsNotLoaded
+			return
+		}
+		if g.err = g.bot.DB.AutoMigrate(&GuildSettingsData{}); g.err != nil {
+			return
+		}
+		g.db = g.bot.DB
+		if g.bot.useRedis() {
+			g.redis, g.err = g.bot.connectRedis()
+		}
+	})
+	return g.err
+}
+
+// Get the settings of the guild (default settings if they were never saved)
+func (g *GuildSettings[T]) Get(ctx context.Context, guildID string) (*T, error) {
+	data, err := g.load(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+	settings := g.defaults()
+	if len(data) == 0 {
+		return settings, nil
+	}
+	// unmarshalling on the default settings to keep default values of new fields
+	return settings, json.Unmarshal(data, settings)
+}
+
+// Save the settings of the guild
+func (g *GuildSettings[T]) Save(ctx context.Context, guildID string, settings *T) error {
+	if err := g.init(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	err = g.db.WithContext(ctx).Save(&GuildSettingsData{Name: g.name, GuildID: guildID, Data: string(data)}).Error
+	if err != nil {
+		return err
+	}
+	return g.cache(ctx, guildID, data)
+}
+
+// Reset the settings of the guild to their default values.
+// If names is empty, every setting is reset.
+func (g *GuildSettings[T]) Reset(ctx context.Context, guildID string, names ...string) error {
+	if len(names) == 0 {
+		return g.Save(ctx, guildID, g.defaults())
+	}
+	settings, err := g.Get(ctx, guildID)
+	if err != nil {
+		return err
+	}
+	def := reflect.ValueOf(g.defaults()).Elem()
+	v := reflect.ValueOf(settings).Elem()
+	for _, n := range names {
+		f := g.field(n)
+		if f == nil {
+			return fmt.Errorf("%w: %s", ErrSettingNotFound, n)
+		}
+		v.FieldByIndex(f.index).Set(def.FieldByIndex(f.index))
+	}
+	return g.Save(ctx, guildID, settings)
+}
+
+// load the settings of the guild as JSON (nil if they were never saved)
+func (g *GuildSettings[T]) load(ctx context.Context, guildID string) ([]byte, error) {
+	if err := g.init(); err != nil {
+		return nil, err
+	}
+	if g.redis != nil {
+		data, err := g.redis.Get(ctx, g.cacheKey(guildID)).Bytes()
+		if err == nil {
+			return data, nil
+		} else if !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+	} else {
+		g.mu.RLock()
+		data, ok := g.memory[guildID]
+		g.mu.RUnlock()
+		if ok {
+			return data, nil
+		}
+	}
+	var row GuildSettingsData
+	err := g.db.WithContext(ctx).Where("name = ? AND guild_id = ?", g.name, guildID).First(&row).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	data := []byte(row.Data)
+	return data, g.cache(ctx, guildID, data)
+}
+
+func (g *GuildSettings[T]) cache(ctx context.Context, guildID string, data []byte) error {
+	if g.redis != nil {
+		return g.redis.Set(ctx, g.cacheKey(guildID), data, guildSettingsCacheTTL).Err()
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.memory[guildID] = data
+	return nil
+}
+
+func (g *GuildSettings[T]) cacheKey(guildID string) string {
+	return fmt.Sprintf("gokord:settings:%s:%s", g.name, guildID)
+}
+
+func (g *GuildSettings[T]) field(name string) *settingField {
+	for _, f := range g.fields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// set the value of the setting after validating it
+func (f *settingField) set(v reflect.Value, value any) error {
+	fv := v.FieldByIndex(f.index)
+	var n float64
+	isNumber := true
+	switch f.kind {
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return f.invalid("not a string")
+		}
+		if len(f.choices) > 0 && !slices.Contains(f.choices, s) {
+			return f.invalid("must be one of " + strings.Join(f.choices, ", "))
+		}
+		n = float64(len([]rune(s)))
+		fv.SetString(s)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return f.invalid("not a boolean")
+		}
+		isNumber = false
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := value.(int64)
+		if !ok || fv.OverflowInt(i) {
+			return f.invalid("not a valid integer")
+		}
+		n = float64(i)
+		fv.SetInt(i)
+	default:
+		fl, ok := value.(float64)
+		if !ok {
+			return f.invalid("not a number")
+		}
+		n = fl
+		fv.SetFloat(fl)
+	}
+	if !isNumber {
+		return nil
+	}
+	if f.min != nil && n < *f.min {
+		return f.invalid(fmt.Sprintf("must be at least %v", *f.min))
+	}
+	if f.max != nil && n > *f.max {
+		return f.invalid(fmt.Sprintf("must be at most %v", *f.max))
+	}
+	return nil
+}
+
+func (f *settingField) invalid(reason string) error {
+	return fmt.Errorf("%w: %s %s", ErrInvalidSettingValue, f.name, reason)
+}
+
+// format the value of the setting to display it in Discord
+func (f *settingField) format(v reflect.Value) string {
+	fv := v.FieldByIndex(f.index)
+	if fv.IsZero() && f.kind == reflect.String {
+		return "*not set*"
+	}
+	switch f.optType {
+	case types.CommandOptionChannel:
+		return "<#" + fv.String() + ">"
+	case types.CommandOptionRole:
+		return "<@&" + fv.String() + ">"
+	case types.CommandOptionUser, types.CommandOptionMentionable:
+		return "<@" + fv.String() + ">"
+	}
+	return fmt.Sprintf("`%v`", fv.Interface())
+}
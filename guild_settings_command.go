@@ -0,0 +1,199 @@
+package gokord
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/interaction"
+)
+
+// Command returns the command /config with the subcommands get, set and reset generated from the settings.
+// It can only be used in guilds by members with AdminPermission.
+func (g *GuildSettings[T]) Command() cmd.CommandBuilder {
+	set := cmd.New("set", "Modify settings of the server").SetHandler(g.set)
+	for _, f := range g.fields {
+		set.AddOption(f.option())
+	}
+	// more than 25 choices are required with every setting and settingsResetAll
+	reset := cmd.NewOption(types.CommandOptionString, "setting", "Setting to reset").IsRequired().IsAutocomplete()
+	g.bot.HandleAutocomplete(g.autocompleteSetting, "config reset", "setting")
+	perm := AdminPermission
+	return cmd.New("config", "Manage the settings of the server").
+		SetPermission(&perm).
+		AddGuard(cmd.GuildOnly(), cmd.RequirePermissions(AdminPermission)).
+		AddSub(cmd.New("get", "Get the settings of the server").SetHandler(g.get)).
+		AddSub(set).
+		AddSub(cmd.New("reset", "Reset settings of the server").AddOption(reset).SetHandler(g.reset))
+}
+
+func (g *GuildSettings[T]) get(s bot.Session, i *event.InteractionCreate, _ cmd.OptionMap, resp *cmd.ResponseBuilder) {
+	settings, err := g.Get(resp.Context(), i.GuildID)
+	if err != nil {
+		g.sendError(s, resp, "getting guild settings", err)
+		return
+	}
+	g.sendSettings(s, resp, settings)
+}
+
+func (g *GuildSettings[T]) set(s bot.Session, i *event.InteractionCreate, optMap cmd.OptionMap, resp *cmd.ResponseBuilder) {
+	resp.IsEphemeral()
+	if len(optMap) == 0 {
+		if err := resp.SetMessage("No setting to modify.").Send(); err != nil {
+			s.Logger().Error("sending guild settings response", "error", err)
+		}
+		return
+	}
+	settings, err := g.Get(resp.Context(), i.GuildID)
+	if err != nil {
+		g.sendError(s, resp, "getting guild settings", err)
+		return
+	}
+	v := reflect.ValueOf(settings).Elem()
+	for _, f := range g.fields {
+		opt, ok := optMap[f.name]
+		if !ok {
+			continue
+		}
+		if err = f.set(v, settingOptionValue(opt)); err != nil {
+			g.sendInvalid(s, resp, err)
+			return
+		}
+	}
+	if g.Validate != nil {
+		if err = g.Validate(i.GuildID, settings); err != nil {
+			g.sendInvalid(s, resp, err)
+			return
+		}
+	}
+	if err = g.Save(resp.Context(), i.GuildID, settings); err != nil {
+		g.sendError(s, resp, "saving guild settings", err)
+		return
+	}
+	if g.OnChange != nil {
+		g.OnChange(s, i.GuildID, settings)
+	}
+	g.sendSettings(s, resp, settings)
+}
+
+func (g *GuildSettings[T]) reset(s bot.Session, i *event.InteractionCreate, optMap cmd.OptionMap, resp *cmd.ResponseBuilder) {
+	var names []string
+	if opt, ok := optMap["setting"]; ok && opt.StringValue() != settingsResetAll {
+		names = append(names, opt.StringValue())
+	}
+	if err := g.Reset(resp.Context(), i.GuildID, names...); err != nil {
+		if errors.Is(err, ErrSettingNotFound) {
+			g.sendInvalid(s, resp, err)
+		} else {
+			g.sendError(s, resp, "resetting guild settings", err)
+		}
+		return
+	}
+	settings, err := g.Get(resp.Context(), i.GuildID)
+	if err != nil {
+		g.sendError(s, resp, "getting guild settings", err)
+		return
+	}
+	if g.OnChange != nil {
+		g.OnChange(s, i.GuildID, settings)
+	}
+	g.sendSettings(s, resp, settings)
+}
+
+// autocompleteSetting suggests the settings containing the input
+func (g *GuildSettings[T]) autocompleteSetting(s bot.Session, _ *event.InteractionCreate, focused *interaction.CommandInteractionDataOption, resp *cmd.ResponseBuilder) {
+	input := strings.ToLower(focused.StringValue())
+	var choices []cmd.CommandChoiceBuilder
+	if strings.Contains(settingsResetAll, input) {
+		choices = append(choices, cmd.NewChoice("Every setting", settingsResetAll))
+	}
+	for _, f := range g.fields {
+		if len(choices) == maxSettings {
+			break
+		}
+		if strings.Contains(f.name, input) {
+			choices = append(choices, cmd.NewChoice(f.name, f.name))
+		}
+	}
+	if err := resp.AddChoices(choices...).Send(); err != nil {
+		s.Logger().Error("sending setting choices", "error", err)
+	}
+}
+
+func (g *GuildSettings[T]) sendSettings(s bot.Session, resp *cmd.ResponseBuilder, settings *T) {
+	v := reflect.ValueOf(settings).Elem()
+	e := cmd.NewEmbed().SetTitle("Settings of the server")
+	for _, f := range g.fields {
+		e.AddField(f.name, f.format(v)+"\n-# "+f.desc, true)
+	}
+	if err := resp.IsEphemeral().DisableMentions().AddEmbedBuilder(e).Send(); err != nil {
+		s.Logger().Error("sending guild settings", "error", err)
+	}
+}
+
+func (g *GuildSettings[T]) sendInvalid(s bot.Session, resp *cmd.ResponseBuilder, err error) {
+	if err = resp.IsEphemeral().SetMessage(err.Error()).Send(); err != nil {
+		s.Logger().Error("sending guild settings response", "error", err)
+	}
+}
+
+func (g *GuildSettings[T]) sendError(s bot.Session, resp *cmd.ResponseBuilder, msg string, err error) {
+	s.Logger().Error(msg, "error", err, "settings", g.name)
+	if err = resp.IsEphemeral().SetMessage("Internal error, please report it").Send(); err != nil {
+		s.Logger().Error("sending error", "error", err)
+	}
+}
+
+// option returns the option of /config set modifying the setting
+func (f *settingField) option() cmd.CommandOptionBuilder {
+	desc := f.desc
+	// Discord does not allow more choices: they are listed in the description and checked by settingField.set
+	if len(f.choices) > maxChoices {
+		desc += " (" + strings.Join(f.choices, ", ") + ")"
+	}
+	opt := cmd.NewOption(f.optType, f.name, desc)
+	switch f.optType {
+	case types.CommandOptionString:
+		if len(f.choices) <= maxChoices {
+			for _, c := range f.choices {
+				opt.AddChoice(cmd.NewChoice(c, c))
+			}
+			// every setting is optional
+			opt.NotRequired()
+		}
+		if f.min != nil {
+			opt.SetMinLength(int(*f.min))
+		}
+		if f.max != nil {
+			opt.SetMaxLength(int(*f.max))
+		}
+	case types.CommandOptionInteger, types.CommandOptionNumber:
+		if f.min != nil {
+			opt.SetMinValue(*f.min)
+		}
+		if f.max != nil {
+			opt.SetMaxValue(*f.max)
+		}
+	}
+	return opt
+}
+
+// settingOptionValue returns the value of the option with the type expected by settingField.set
+func settingOptionValue(opt *interaction.CommandInteractionDataOption) any {
+	switch opt.Type {
+	case types.CommandOptionString:
+		return opt.StringValue()
+	case types.CommandOptionBoolean:
+		return opt.BoolValue()
+	case types.CommandOptionInteger:
+		return opt.IntValue()
+	case types.CommandOptionNumber:
+		return opt.FloatValue()
+	}
+	// channel, role, user and mentionable options contain the ID
+	return opt.Value
+}
@@ -0,0 +1,145 @@
+package gokord
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/nyttikord/gokord/discord/types"
+)
+
+func TestParseSettingField(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     reflect.Type
+		tag     reflect.StructTag
+		optType types.CommandOption
+		desc    string
+		choices []string
+		min     *float64
+		max     *float64
+		err     error
+	}{
+		{"string", reflect.TypeFor[string](), `setting:"lang" desc:"Language"`,
+			types.CommandOptionString, "Language", nil, nil, nil, nil},
+		{"default description", reflect.TypeFor[string](), `setting:"lang"`,
+			types.CommandOptionString, "lang", nil, nil, nil, nil},
+		{"channel", reflect.TypeFor[string](), `setting:"logs" type:"channel"`,
+			types.CommandOptionChannel, "logs", nil, nil, nil, nil},
+		{"mentionable", reflect.TypeFor[string](), `setting:"ping" type:"mentionable"`,
+			types.CommandOptionMentionable, "ping", nil, nil, nil, nil},
+		{"choices", reflect.TypeFor[string](), `setting:"lang" choices:"en,fr"`,
+			types.CommandOptionString, "lang", []string{"en", "fr"}, nil, nil, nil},
+		{"bool", reflect.TypeFor[bool](), `setting:"enabled"`,
+			types.CommandOptionBoolean, "enabled", nil, nil, nil, nil},
+		{"integer bounds", reflect.TypeFor[int32](), `setting:"warns" min:"1" max:"10"`,
+			types.CommandOptionInteger, "warns", nil, bound(1), bound(10), nil},
+		{"number", reflect.TypeFor[float64](), `setting:"ratio" max:"0.5"`,
+			types.CommandOptionNumber, "ratio", nil, nil, bound(.5), nil},
+		{"invalid name", reflect.TypeFor[string](), `setting:"Lang"`, 0, "", nil, nil, nil, ErrInvalidSettingName},
+		{"reserved name", reflect.TypeFor[string](), `setting:"all"`, 0, "", nil, nil, nil, ErrInvalidSettingName},
+		{"unknown type", reflect.TypeFor[string](), `setting:"logs" type:"emoji"`,
+			0, "", nil, nil, nil, ErrUnsupportedSettingType},
+		{"unsupported kind", reflect.TypeFor[[]string](), `setting:"list"`,
+			0, "", nil, nil, nil, ErrUnsupportedSettingType},
+		{"invalid bound", reflect.TypeFor[int](), `setting:"warns" min:"one"`,
+			0, "", nil, nil, nil, ErrInvalidSettingValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := reflect.StructField{Name: "Field", Type: tt.typ, Tag: tt.tag, Index: []int{0}}
+			got, err := parseSettingField(f, tt.tag.Get("setting"))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if got.optType != tt.optType {
+				t.Errorf("got type %v, want %v", got.optType, tt.optType)
+			}
+			if got.desc != tt.desc {
+				t.Errorf("got description %q, want %q", got.desc, tt.desc)
+			}
+			if !slices.Equal(got.choices, tt.choices) {
+				t.Errorf("got choices %v, want %v", got.choices, tt.choices)
+			}
+			if !equalBound(got.min, tt.min) {
+				t.Errorf("got min %v, want %v", got.min, tt.min)
+			}
+			if !equalBound(got.max, tt.max) {
+				t.Errorf("got max %v, want %v", got.max, tt.max)
+			}
+		})
+	}
+}
+
+func bound(v float64) *float64 {
+	return &v
+}
+
+func equalBound(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func TestSettingFieldSet(t *testing.T) {
+	type settings struct {
+		Lang  string  `setting:"lang" choices:"en,fr"`
+		Name  string  `setting:"name" min:"2" max:"4"`
+		On    bool    `setting:"on"`
+		Warns int8    `setting:"warns" min:"1" max:"10"`
+		Ratio float64 `setting:"ratio" max:"0.5"`
+	}
+	g, err := NewGuildSettings[settings](nil, "test", func() *settings { return &settings{} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		field string
+		value any
+		valid bool
+	}{
+		{"lang", "fr", true},
+		{"lang", "de", false},
+		{"lang", 1., false},
+		{"name", "abcd", true},
+		{"name", "été", true},
+		{"name", "a", false},
+		{"name", "abcde", false},
+		{"on", true, true},
+		{"on", "true", false},
+		{"warns", int64(10), true},
+		{"warns", int64(0), false},
+		{"warns", int64(11), false},
+		{"warns", int64(300), false},
+		{"warns", 5., false},
+		{"ratio", .5, true},
+		{"ratio", .6, false},
+	}
+	for _, tt := range tests {
+		v := reflect.ValueOf(&settings{}).Elem()
+		err := g.field(tt.field).set(v, tt.value)
+		if tt.valid && err != nil {
+			t.Errorf("set %s to %v: %v", tt.field, tt.value, err)
+		} else if !tt.valid && !errors.Is(err, ErrInvalidSettingValue) {
+			t.Errorf("set %s to %v: got error %v, want %v", tt.field, tt.value, err, ErrInvalidSettingValue)
+		}
+	}
+}
+
+func TestNewGuildSettingsErrors(t *testing.T) {
+	type duplicated struct {
+		A string `setting:"a"`
+		B string `setting:"a"`
+	}
+	if _, err := NewGuildSettings[duplicated](nil, "test", nil); !errors.Is(err, ErrInvalidSettingName) {
+		t.Errorf("duplicated: got error %v, want %v", err, ErrInvalidSettingName)
+	}
+	if _, err := NewGuildSettings[string](nil, "test", nil); !errors.Is(err, ErrSettingsNotStruct) {
+		t.Errorf("not a struct: got error %v, want %v", err, ErrSettingsNotStruct)
+	}
+}
@@ -0,0 +1,190 @@
+package gokord
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/guild"
+)
+
+var ErrHookFailed = errors.New("lifecycle hook failed")
+
+// LifecycleHook is called during the lifecycle of the Bot
+type LifecycleHook func(ctx context.Context, s bot.Session) error
+
+// GuildHook is called when the Bot joins or leaves a guild
+type GuildHook func(ctx context.Context, s bot.Session, g *guild.Guild) error
+
+// hooks of the Bot, called in the order of their addition
+type hooks struct {
+	beforeConnect  []LifecycleHook
+	firstReady     []LifecycleHook
+	ready          []LifecycleHook
+	commandsSynced []LifecycleHook
+	guildJoin      []GuildHook
+	guildLeave     []GuildHook
+	beforeShutdown []LifecycleHook
+
+	// guilds known by each shard, used to differentiate joins from guilds becoming available
+	guilds   map[bot.Session]*shardGuilds
+	guildsMu sync.Mutex
+}
+
+// shardGuilds are the guilds known by a shard
+type shardGuilds struct {
+	known map[string]struct{}
+	// ready is closed once the guilds sent with Ready are known
+	ready chan struct{}
+}
+
+// shardGuilds returns the guilds known by the shard of the session.
+// Bot.hooks.guildsMu must be locked.
+func (b *Bot) shardGuilds(s bot.Session) *shardGuilds {
+	if b.hooks.guilds == nil {
+		b.hooks.guilds = make(map[bot.Session]*shardGuilds)
+	}
+	sg, ok := b.hooks.guilds[s]
+	if !ok {
+		sg = &shardGuilds{known: make(map[string]struct{}), ready: make(chan struct{})}
+		b.hooks.guilds[s] = sg
+	}
+	return sg
+}
+
+// OnBeforeConnect adds a LifecycleHook called before the connection to Discord.
+// An error aborts Open.
+func (b *Bot) OnBeforeConnect(h LifecycleHook) {
+	b.hooks.beforeConnect = append(b.hooks.beforeConnect, h)
+}
+
+// OnFirstReady adds a LifecycleHook called when the Bot is ready for the first time (with the session of the first
+// shard run by the process if the Bot is sharded).
+// An error aborts Open.
+func (b *Bot) OnFirstReady(h LifecycleHook) {
+	b.hooks.firstReady = append(b.hooks.firstReady, h)
+}
+
+// OnReady adds a LifecycleHook called each time the Bot is ready, including after a reconnection (for each shard if the
+// Bot is sharded).
+// An error is logged.
+func (b *Bot) OnReady(h LifecycleHook) {
+	b.hooks.ready = append(b.hooks.ready, h)
+}
+
+// OnCommandsSynced adds a LifecycleHook called after the commands are registered.
+// It is only called by the primary shard (see Bot.PrimaryShard).
+// An error is logged.
+func (b *Bot) OnCommandsSynced(h LifecycleHook) {
+	b.hooks.commandsSynced = append(b.hooks.commandsSynced, h)
+}
+
+// OnGuildJoin adds a GuildHook called when the Bot joins a guild.
+// An error is logged.
+func (b *Bot) OnGuildJoin(h GuildHook) {
+	b.hooks.guildJoin = append(b.hooks.guildJoin, h)
+}
+
+// OnGuildLeave adds a GuildHook called when the Bot leaves a guild (or is kicked).
+// An error is logged.
+func (b *Bot) OnGuildLeave(h GuildHook) {
+	b.hooks.guildLeave = append(b.hooks.guildLeave, h)
+}
+
+// OnBeforeShutdown adds a LifecycleHook called when the Bot is closed, before draining running handlers (the session
+// is still open).
+// An error is logged.
+func (b *Bot) OnBeforeShutdown(h LifecycleHook) {
+	b.hooks.beforeShutdown = append(b.hooks.beforeShutdown, h)
+}
+
+// OnAfterShutdown is an alias of OnShutdown: the ShutdownHook is called by Close after the session is closed, in the
+// same list as the hooks added with OnShutdown.
+func (b *Bot) OnAfterShutdown(h ShutdownHook) {
+	b.OnShutdown(h)
+}
+
+// runHooks calls every LifecycleHook and stops at the first error
+func runHooks(ctx context.Context, s bot.Session, hs []LifecycleHook) error {
+	for _, h := range hs {
+		if err := h(ctx, s); err != nil {
+			return errors.Join(ErrHookFailed, err)
+		}
+	}
+	return nil
+}
+
+// logHooks calls every LifecycleHook and logs errors
+func (b *Bot) logHooks(ctx context.Context, s bot.Session, name string, hs []LifecycleHook) {
+	for _, h := range hs {
+		if err := h(ctx, s); err != nil {
+			b.Logger.Error("running hook", "hook", name, "error", err)
+		}
+	}
+}
+
+// onReadyHooks registers the guilds of the shard and calls the hooks
+func (b *Bot) onReadyHooks(ctx context.Context, s bot.Session, r *event.Ready) {
+	b.hooks.guildsMu.Lock()
+	sg := b.shardGuilds(s)
+	for _, g := range r.Guilds {
+		sg.known[g.ID] = struct{}{}
+	}
+	select {
+	case <-sg.ready:
+		// Ready is received again after a reconnection
+	default:
+		close(sg.ready)
+	}
+	b.hooks.guildsMu.Unlock()
+	b.logHooks(ctx, s, "ready", b.hooks.ready)
+}
+
+// onGuildCreateHooks registers the guild scoped commands and calls the GuildHook added with OnGuildJoin if the guild
+// was not known by the shard.
+// GuildCreate is also received for every guild of Ready after each connection: these guilds are known.
+func (b *Bot) onGuildCreateHooks(ctx context.Context, s bot.Session, g *event.GuildCreate) {
+	b.hooks.guildsMu.Lock()
+	sg := b.shardGuilds(s)
+	b.hooks.guildsMu.Unlock()
+	// handlers are concurrent: Ready, always received before, may not be handled yet
+	select {
+	case <-sg.ready:
+	case <-ctx.Done():
+		return
+	}
+	b.hooks.guildsMu.Lock()
+	_, known := sg.known[g.ID]
+	sg.known[g.ID] = struct{}{}
+	b.hooks.guildsMu.Unlock()
+	if known {
+		return
+	}
+	b.syncJoinedGuildCommands(s, g.ID)
+	for _, h := range b.hooks.guildJoin {
+		if err := h(ctx, s, g.Guild); err != nil {
+			b.Logger.Error("running hook", "hook", "guild join", "error", err, "guild", g.ID)
+		}
+	}
+}
+
+// onGuildDeleteHooks calls the GuildHook added with OnGuildLeave if the guild is not unavailable (outage)
+func (b *Bot) onGuildDeleteHooks(ctx context.Context, s bot.Session, g *event.GuildDelete) {
+	if g.Unavailable {
+		return
+	}
+	b.hooks.guildsMu.Lock()
+	delete(b.shardGuilds(s).known, g.ID)
+	b.hooks.guildsMu.Unlock()
+	guildLeft := g.Guild
+	if g.BeforeDelete != nil {
+		guildLeft = g.BeforeDelete
+	}
+	for _, h := range b.hooks.guildLeave {
+		if err := h(ctx, s, guildLeft); err != nil {
+			b.Logger.Error("running hook", "hook", "guild leave", "error", err, "guild", g.ID)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package gokord
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/interaction"
+)
+
+// InteractionTimeout is the time given by Discord to respond to an interaction received with HTTP
+const InteractionTimeout = 3 * time.Second
+
+// maxInteractionSize is the maximum size of the body of an interaction received with HTTP
+const maxInteractionSize = 1 << 20
+
+var (
+	ErrInvalidPublicKey            = errors.New("invalid public key")
+	ErrInteractionExpired          = errors.New("interaction expired (response sent after the timeout)")
+	ErrInteractionAlreadyResponded = errors.New("interaction already responded")
+)
+
+// interactionsEndpoint is the http.Handler receiving interactions from Discord
+type interactionsEndpoint struct {
+	bot     *Bot
+	key     ed25519.PublicKey
+	session bot.Session
+	newResp func(s bot.Session, i *event.InteractionCreate) *cmd.ResponseBuilder
+}
+
+// OpenHTTP sets up the Bot to receive interactions with HTTP instead of the gateway (non-blocking instruction).
+// It returns the http.Handler to serve at the interactions endpoint URL set in the Discord developer portal.
+// publicKey is the hex encoded public key of the application, used to verify the requests.
+//
+// Commands, autocompletion, message components and modals work like with Open, but event handlers are never called
+// (there is no gateway connection): FirstReady hooks are called once the Bot is set up.
+// Initial responses must be sent in InteractionTimeout: use cmd.ResponseBuilder.IsDeferred for longer handlers.
+//
+// Use Close to stop the Bot.
+func (b *Bot) OpenHTTP(ctx context.Context, publicKey string) (http.Handler, error) {
+	key, err := hex.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	b.sessionMu.Lock()
+	defer b.sessionMu.Unlock()
+	if b.session != nil {
+		return nil, ErrBotAlreadyOpen
+	}
+	dg, err := b.newSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	app, err := dg.UserAPI().Application("@me")
+	if err == nil {
+		b.user, err = dg.UserAPI().User("@me")
+	}
+	if err == nil {
+		err = runHooks(ctx, dg, b.hooks.firstReady)
+	}
+	if err != nil {
+		b.stopTimers()
+		b.cancelLifetime()
+		return nil, errors.Join(ErrOpeningBot, err)
+	}
+	b.appID = app.ID
+	b.http = true
+	b.start(dg)
+	b.setupCommandMap()
+
+	if b.AfterInit != nil {
+		b.AfterInit(dg)
+	}
+	return &interactionsEndpoint{
+		bot:     b,
+		key:     key,
+		session: dg,
+		newResp: b.responseFactory(),
+	}, nil
+}
+
+func (e *interactionsEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxInteractionSize))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if !e.verify(r.Header, body) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var i interaction.Interaction
+	if err = json.Unmarshal(body, &i); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if i.Type == types.InteractionPing {
+		e.writeResponse(w, &interaction.Response{Type: types.InteractionResponsePong})
+		return
+	}
+	if e.bot.Debug {
+		e.bot.Logger.Debug("interaction received")
+		e.bot.Logger.Debug(string(body))
+	}
+
+	res := newHTTPResponder(e.session, &i)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ev := &event.InteractionCreate{Interaction: &i}
+		// the handler may run after the response to the request (e.g., if it is deferred)
+		ctx := context.WithoutCancel(r.Context())
+		e.bot.handleInteraction(e.session, ev, func() *cmd.ResponseBuilder {
+			return e.newResp(e.session, ev).SetResponder(res.respond).SetContext(ctx)
+		})
+	}()
+
+	timer := time.NewTimer(InteractionTimeout)
+	defer timer.Stop()
+	select {
+	case resp := <-res.responses:
+		if resp == nil {
+			// sent with the API
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		e.writeResponse(w, resp)
+	case <-done:
+		// the handler may have responded just before returning
+		select {
+		case resp := <-res.responses:
+			if resp == nil {
+				w.WriteHeader(http.StatusAccepted)
+			} else {
+				e.writeResponse(w, resp)
+			}
+		default:
+			res.expire()
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	case <-timer.C:
+		res.expire()
+		e.bot.Logger.Warn("interaction not responded in time", "interaction", i.ID)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	case <-r.Context().Done():
+		// the connection was closed by Discord: nothing can be written
+		res.expire()
+		e.bot.Logger.Warn("interaction request cancelled", "interaction", i.ID, "error", r.Context().Err())
+	}
+}
+
+// verify returns true if the request was signed by Discord
+func (e *interactionsEndpoint) verify(h http.Header, body []byte) bool {
+	sig, err := hex.DecodeString(h.Get("X-Signature-Ed25519"))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	ts := h.Get("X-Signature-Timestamp")
+	if ts == "" {
+		return false
+	}
+	return ed25519.Verify(e.key, append([]byte(ts), body...), sig)
+}
+
+// writeResponse writes the interaction.Response in the body of the HTTP response
+func (e *interactionsEndpoint) writeResponse(w http.ResponseWriter, r *interaction.Response) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		e.bot.Logger.Error("marshalling interaction response", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(b); err != nil {
+		e.bot.Logger.Error("writing interaction response", "error", err)
+	}
+}
+
+// httpResponder sends the initial response of an interaction in the body of the HTTP response
+type httpResponder struct {
+	session     bot.Session
+	interaction *interaction.Interaction
+	// responses receives the initial response, or nil if it was sent with the API
+	responses chan *interaction.Response
+	responded bool
+	expired   bool
+	mu        sync.Mutex
+}
+
+func newHTTPResponder(s bot.Session, i *interaction.Interaction) *httpResponder {
+	return &httpResponder{session: s, interaction: i, responses: make(chan *interaction.Response, 1)}
+}
+
+// respond sends the initial response.
+// Responses with files cannot be sent in the body: they are sent with the API.
+func (r *httpResponder) respond(resp *interaction.Response) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.responded {
+		return ErrInteractionAlreadyResponded
+	}
+	if r.expired {
+		return ErrInteractionExpired
+	}
+	if resp.Data != nil && len(resp.Data.Files) > 0 {
+		if err := r.session.InteractionAPI().Respond(r.interaction, resp); err != nil {
+			return err
+		}
+		resp = nil
+	}
+	r.responded = true
+	r.responses <- resp
+	return nil
+}
+
+// expire prevents sending the initial response
+func (r *httpResponder) expire() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.expired = true
+}
@@ -0,0 +1,51 @@
+package gokord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"net/http"
+	"testing"
+)
+
+func TestInteractionsEndpointVerify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, other, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"type":1}`)
+	const ts = "1700000000"
+	sign := func(k ed25519.PrivateKey, msg string) string {
+		return hex.EncodeToString(ed25519.Sign(k, []byte(msg)))
+	}
+	tests := []struct {
+		name string
+		sig  string
+		ts   string
+		body []byte
+		want bool
+	}{
+		{"valid", sign(priv, ts+string(body)), ts, body, true},
+		{"other key", sign(other, ts+string(body)), ts, body, false},
+		{"other timestamp", sign(priv, ts+string(body)), "1700000001", body, false},
+		{"other body", sign(priv, ts+string(body)), ts, []byte(`{"type":2}`), false},
+		{"missing timestamp", sign(priv, string(body)), "", body, false},
+		{"missing signature", "", ts, body, false},
+		{"not hex", "not a signature", ts, body, false},
+		{"truncated signature", sign(priv, ts+string(body))[:64], ts, body, false},
+	}
+	e := &interactionsEndpoint{key: pub}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			h.Set("X-Signature-Ed25519", tt.sig)
+			h.Set("X-Signature-Timestamp", tt.ts)
+			if got := e.verify(h, tt.body); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
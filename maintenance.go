@@ -0,0 +1,162 @@
+package gokord
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// MaintenanceRedisKey is the redis key containing the Maintenance shared between instances
+	MaintenanceRedisKey = "gokord:maintenance"
+	// maintenancePollInterval is the duration between two reads of MaintenanceRedisKey
+	maintenancePollInterval = 15 * time.Second
+)
+
+// MaintenanceMessages are sent when a command is disabled by the Maintenance, indexed by the locale of the user.
+//
+// The message linked with "" is used if the locale is not present.
+var MaintenanceMessages = map[string]string{
+	"":   "This command is disabled during the maintenance of the bot, try again later.",
+	"fr": "Cette commande est désactivée pendant la maintenance du bot, réessaye plus tard.",
+}
+
+// Maintenance disables commands of the Bot, except for its owners
+type Maintenance struct {
+	Enabled bool `toml:"enabled" json:"enabled"`
+	// Commands disabled (every command if empty).
+	// A subcommand is disabled with "command subcommand".
+	Commands []string `toml:"commands" json:"commands"`
+	// Message sent when a command is disabled, MaintenanceMessages is used if empty
+	Message string `toml:"message" json:"message"`
+	// Status of the Bot (custom status) while the Maintenance is enabled, the Status of the Bot is kept if empty
+	Status string `toml:"status" json:"status"`
+}
+
+// MaintenanceConfig can be implemented by a BaseConfig to set the Maintenance on start
+type MaintenanceConfig interface {
+	// GetMaintenance returns the Maintenance to apply (nil to keep Bot.Maintenance)
+	GetMaintenance() *Maintenance
+}
+
+// Disables returns true if the command is disabled by the Maintenance.
+//
+// name is the name of the command and sub the name of its subcommand (empty if there is no subcommand)
+func (m *Maintenance) Disables(name string, sub string) bool {
+	if m == nil || !m.Enabled {
+		return false
+	}
+	if len(m.Commands) == 0 {
+		return true
+	}
+	return slices.Contains(m.Commands, name) || (sub != "" && slices.Contains(m.Commands, name+" "+sub))
+}
+
+// GetMaintenance returns the current Maintenance of the Bot (nil if there is no Maintenance)
+func (b *Bot) GetMaintenance() *Maintenance {
+	b.maintenanceMu.RLock()
+	defer b.maintenanceMu.RUnlock()
+	return b.maintenance
+}
+
+// SetMaintenance of the Bot (nil disables it).
+// The Maintenance is shared with other instances through redis if the Bot uses it.
+//
+// The status of the Bot is updated at the next tick of the status timer.
+func (b *Bot) SetMaintenance(m *Maintenance) error {
+	b.setMaintenance(m, b.maintenanceRedis != nil)
+	if b.maintenanceRedis == nil {
+		return nil
+	}
+	if m == nil || !m.Enabled {
+		return b.maintenanceRedis.Del(b.context(), MaintenanceRedisKey).Err()
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return b.maintenanceRedis.Set(b.context(), MaintenanceRedisKey, data, 0).Err()
+}
+
+// setMaintenance of the Bot.
+// shared is true if the Maintenance is also stored in redis.
+func (b *Bot) setMaintenance(m *Maintenance, shared bool) {
+	if m != nil && !m.Enabled {
+		m = nil
+	}
+	b.maintenanceMu.Lock()
+	defer b.maintenanceMu.Unlock()
+	b.maintenanceShared = shared
+	if (b.maintenance == nil) != (m == nil) {
+		b.logger().Info("maintenance changed", "enabled", m != nil)
+	}
+	b.maintenance = m
+}
+
+// setupMaintenance loads the Maintenance from the config and from redis
+func (b *Bot) setupMaintenance() {
+	m := b.Maintenance
+	if cfg, ok := b.Config.(MaintenanceConfig); ok && cfg.GetMaintenance() != nil {
+		m = cfg.GetMaintenance()
+	}
+	b.setMaintenance(m, false)
+	if !b.useRedis() {
+		return
+	}
+	c, err := b.connectRedis()
+	if err != nil {
+		b.Logger.Error("connecting to redis for maintenance", "error", err)
+		return
+	}
+	b.maintenanceRedis = c
+	ctx := b.lifetime
+	b.maintenanceCancel = NewTimer(maintenancePollInterval, func(chan<- interface{}) {
+		data, err := c.Get(ctx, MaintenanceRedisKey).Bytes()
+		if errors.Is(err, redis.Nil) {
+			b.maintenanceMu.RLock()
+			shared := b.maintenanceShared
+			b.maintenanceMu.RUnlock()
+			// keeping the local maintenance (e.g., from the config) if it was not shared
+			if shared {
+				b.setMaintenance(nil, false)
+			}
+			return
+		} else if err != nil {
+			b.Logger.Error("reading maintenance from redis", "error", err)
+			return
+		}
+		var m Maintenance
+		if err = json.Unmarshal(data, &m); err != nil {
+			b.Logger.Error("decoding maintenance from redis", "error", err)
+			return
+		}
+		b.setMaintenance(&m, true)
+	})
+}
+
+// maintenanceMessage returns the message to send if the command is disabled by the Maintenance.
+// It returns false if the command is not disabled.
+func (b *Bot) maintenanceMessage(i *event.InteractionCreate) (string, bool) {
+	m := b.GetMaintenance()
+	if m == nil {
+		return "", false
+	}
+	data := i.CommandData()
+	var sub string
+	if len(data.Options) > 0 && data.Options[0].Type == types.CommandOptionSubCommand {
+		sub = data.Options[0].Name
+	}
+	if !m.Disables(data.Name, sub) || b.IsOwner(cmd.InteractionUserID(i)) {
+		return "", false
+	}
+	if m.Message != "" {
+		return m.Message, true
+	}
+	return cmd.LocalizedMessage(MaintenanceMessages, string(i.Locale)), true
+}
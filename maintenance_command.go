@@ -0,0 +1,92 @@
+package gokord
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+)
+
+// maintenanceCommand returns the owner-only command managing the Maintenance
+func (b *Bot) maintenanceCommand() cmd.CommandBuilder {
+	return cmd.New("maintenance", "Manage the maintenance of the bot").
+		AddGuard(cmd.OwnerOnly()).
+		AddContext(types.InteractionContextGuild).
+		AddContext(types.InteractionContextBotDM).
+		AddSub(cmd.New("on", "Enable the maintenance").
+			AddOption(cmd.NewOption(
+				types.CommandOptionString, "commands", "Commands to disable, separated by commas (every command if not set)",
+			)).
+			AddOption(cmd.NewOption(types.CommandOptionString, "message", "Message sent when a command is disabled")).
+			AddOption(cmd.NewOption(types.CommandOptionString, "status", "Status of the bot during the maintenance")).
+			SetHandler(b.maintenanceOn)).
+		AddSub(cmd.New("off", "Disable the maintenance").
+			SetHandler(b.maintenanceOff)).
+		AddSub(cmd.New("status", "Get the current maintenance").
+			SetHandler(b.maintenanceStatus))
+}
+
+func (b *Bot) maintenanceOn(_ bot.Session, _ *event.InteractionCreate, optMap cmd.OptionMap, resp *cmd.ResponseBuilder) {
+	m := &Maintenance{Enabled: true}
+	if opt, ok := optMap["commands"]; ok {
+		for _, c := range strings.Split(opt.StringValue(), ",") {
+			// "/command  sub" -> "command sub"
+			c = strings.Join(strings.Fields(strings.TrimPrefix(strings.TrimSpace(c), "/")), " ")
+			if c != "" {
+				m.Commands = append(m.Commands, c)
+			}
+		}
+	}
+	if opt, ok := optMap["message"]; ok {
+		m.Message = opt.StringValue()
+	}
+	if opt, ok := optMap["status"]; ok {
+		m.Status = opt.StringValue()
+	}
+	b.sendMaintenanceUpdate(resp, m)
+}
+
+func (b *Bot) maintenanceOff(_ bot.Session, _ *event.InteractionCreate, _ cmd.OptionMap, resp *cmd.ResponseBuilder) {
+	b.sendMaintenanceUpdate(resp, nil)
+}
+
+func (b *Bot) maintenanceStatus(_ bot.Session, _ *event.InteractionCreate, _ cmd.OptionMap, resp *cmd.ResponseBuilder) {
+	err := resp.IsEphemeral().SetMessage(formatMaintenance(b.GetMaintenance())).Send()
+	if err != nil {
+		b.Logger.Error("sending maintenance status", "error", err)
+	}
+}
+
+func (b *Bot) sendMaintenanceUpdate(resp *cmd.ResponseBuilder, m *Maintenance) {
+	resp.IsEphemeral()
+	msg := formatMaintenance(m)
+	if err := b.SetMaintenance(m); err != nil {
+		b.Logger.Error("sharing maintenance", "error", err)
+		msg += "\n-# The maintenance could not be shared with other instances."
+	}
+	if err := resp.SetMessage(msg).Send(); err != nil {
+		b.Logger.Error("sending maintenance update", "error", err)
+	}
+}
+
+func formatMaintenance(m *Maintenance) string {
+	if m == nil || !m.Enabled {
+		return "The maintenance is disabled."
+	}
+	s := "The maintenance is enabled for "
+	if len(m.Commands) == 0 {
+		s += "every command."
+	} else {
+		s += fmt.Sprintf("`/%s`.", strings.Join(m.Commands, "`, `/"))
+	}
+	if m.Message != "" {
+		s += "\nMessage: " + m.Message
+	}
+	if m.Status != "" {
+		s += "\nStatus: " + m.Status
+	}
+	return s
+}
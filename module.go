@@ -0,0 +1,515 @@
+package gokord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/interaction"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrModuleDuplicated      = errors.New("module is added twice")
+	ErrModuleNotFound        = errors.New("module not found")
+	ErrModuleDependencyCycle = errors.New("cycle in the dependencies of modules")
+	ErrModuleNotToggleable   = errors.New("module cannot be disabled")
+	ErrModuleWithoutDB       = errors.New("module requires a database (Bot.DB is nil)")
+)
+
+// Module bundles a reusable feature of a Bot: its commands, handlers, configs, models and innovations.
+//
+// Add it with Bot.AddModule, before calling Bot.SetupConfigs.
+type Module interface {
+	// DependsOn other modules (by name), which are set up before this Module
+	DependsOn(names ...string) Module
+	// AddCommand to the Module
+	AddCommand(c ...cmd.CommandBuilder) Module
+	// AddHandler to the Module (see Bot.AddHandler)
+	AddHandler(h ...any) Module
+	// HandleComponent registers the handler called when the message component with the given custom ID is used
+	HandleComponent(id string, h cmd.ComponentHandler) Module
+	// HandleModal registers the handler called when the modal with the given custom ID is submitted
+	HandleModal(id string, h cmd.ModalHandler) Module
+	// AddConfig loaded by Bot.SetupConfigs
+	AddConfig(c ...*ConfigInfo) Module
+	// AddModel migrated when the Bot is opened
+	AddModel(m ...any) Module
+	// AddInnovation to the Bot
+	AddInnovation(i ...*Innovation) Module
+	// OnSetup is called when the Module is set up, after its dependencies and the migration of its models
+	OnSetup(fn func(b *Bot) error) Module
+	// IsToggleable informs that the Module can be enabled and disabled per guild
+	IsToggleable() Module
+	// GetName returns the name of the Module
+	GetName() string
+	// GetDependencies returns the names of the modules required by the Module
+	GetDependencies() []string
+	// GetCommands returns the commands of the Module
+	GetCommands() []cmd.CommandBuilder
+	// GetHandlers returns the handlers of the Module
+	GetHandlers() []any
+	// GetComponents returns the message component handlers of the Module, indexed by custom ID
+	GetComponents() map[string]cmd.ComponentHandler
+	// GetModals returns the modal handlers of the Module, indexed by custom ID
+	GetModals() map[string]cmd.ModalHandler
+	// GetConfigs returns the ConfigInfo of the Module
+	GetConfigs() []*ConfigInfo
+	// GetModels returns the models of the Module
+	GetModels() []any
+	// GetInnovations returns the Innovation of the Module
+	GetInnovations() []*Innovation
+	// GetSetup returns the function called when the Module is set up (may be nil)
+	GetSetup() func(b *Bot) error
+	// Toggleable returns true if the Module can be enabled and disabled per guild
+	Toggleable() bool
+}
+
+type moduleCreator struct {
+	Name         string
+	Dependencies []string
+	Commands     []cmd.CommandBuilder
+	Handlers     []any
+	Components   map[string]cmd.ComponentHandler
+	Modals       map[string]cmd.ModalHandler
+	Configs      []*ConfigInfo
+	Models       []any
+	Innovations  []*Innovation
+	Setup        func(b *Bot) error
+	CanToggle    bool
+}
+
+// NewModule creates a new Module
+func NewModule(name string) Module {
+	return &moduleCreator{
+		Name:       name,
+		Components: map[string]cmd.ComponentHandler{},
+		Modals:     map[string]cmd.ModalHandler{},
+	}
+}
+
+func (m *moduleCreator) DependsOn(names ...string) Module {
+	m.Dependencies = append(m.Dependencies, names...)
+	return m
+}
+
+func (m *moduleCreator) AddCommand(c ...cmd.CommandBuilder) Module {
+	m.Commands = append(m.Commands, c...)
+	return m
+}
+
+func (m *moduleCreator) AddHandler(h ...any) Module {
+	m.Handlers = append(m.Handlers, h...)
+	return m
+}
+
+func (m *moduleCreator) HandleComponent(id string, h cmd.ComponentHandler) Module {
+	m.Components[id] = h
+	return m
+}
+
+func (m *moduleCreator) HandleModal(id string, h cmd.ModalHandler) Module {
+	m.Modals[id] = h
+	return m
+}
+
+func (m *moduleCreator) AddConfig(c ...*ConfigInfo) Module {
+	m.Configs = append(m.Configs, c...)
+	return m
+}
+
+func (m *moduleCreator) AddModel(models ...any) Module {
+	m.Models = append(m.Models, models...)
+	return m
+}
+
+func (m *moduleCreator) AddInnovation(i ...*Innovation) Module {
+	m.Innovations = append(m.Innovations, i...)
+	return m
+}
+
+func (m *moduleCreator) OnSetup(fn func(b *Bot) error) Module {
+	m.Setup = fn
+	return m
+}
+
+func (m *moduleCreator) IsToggleable() Module {
+	m.CanToggle = true
+	return m
+}
+
+func (m *moduleCreator) GetName() string {
+	return m.Name
+}
+
+func (m *moduleCreator) GetDependencies() []string {
+	return m.Dependencies
+}
+
+func (m *moduleCreator) GetCommands() []cmd.CommandBuilder {
+	return m.Commands
+}
+
+func (m *moduleCreator) GetHandlers() []any {
+	return m.Handlers
+}
+
+func (m *moduleCreator) GetComponents() map[string]cmd.ComponentHandler {
+	return m.Components
+}
+
+func (m *moduleCreator) GetModals() map[string]cmd.ModalHandler {
+	return m.Modals
+}
+
+func (m *moduleCreator) GetConfigs() []*ConfigInfo {
+	return m.Configs
+}
+
+func (m *moduleCreator) GetModels() []any {
+	return m.Models
+}
+
+func (m *moduleCreator) GetInnovations() []*Innovation {
+	return m.Innovations
+}
+
+func (m *moduleCreator) GetSetup() func(b *Bot) error {
+	return m.Setup
+}
+
+func (m *moduleCreator) Toggleable() bool {
+	return m.CanToggle
+}
+
+// DisabledModule is a toggleable Module disabled in a guild
+type DisabledModule struct {
+	GuildID string `gorm:"primaryKey"`
+	Module  string `gorm:"primaryKey"`
+}
+
+// modules of the Bot
+type modules struct {
+	list  []Module
+	once  sync.Once
+	err   error
+	state map[string]map[string]struct{} // state contains the modules disabled in each guild loaded
+	mu    sync.RWMutex
+}
+
+// AddModule to the Bot.
+// It must be called before Bot.SetupConfigs to load the configs of the Module.
+func (b *Bot) AddModule(m ...Module) {
+	b.modules.list = append(b.modules.list, m...)
+}
+
+// Modules returns the modules of the Bot
+func (b *Bot) Modules() []Module {
+	return b.modules.list
+}
+
+// module returns the Module with the given name (nil if it does not exist)
+func (b *Bot) module(name string) Module {
+	i := slices.IndexFunc(b.modules.list, func(m Module) bool { return m.GetName() == name })
+	if i == -1 {
+		return nil
+	}
+	return b.modules.list[i]
+}
+
+// moduleConfigs returns the ConfigInfo of every Module
+func (b *Bot) moduleConfigs() []*ConfigInfo {
+	var cfgs []*ConfigInfo
+	for _, m := range b.modules.list {
+		cfgs = append(cfgs, m.GetConfigs()...)
+	}
+	return cfgs
+}
+
+// sortModules returns the modules sorted by dependencies
+func (b *Bot) sortModules() ([]Module, error) {
+	sorted := make([]Module, 0, len(b.modules.list))
+	// visiting is true while the dependencies of the module are visited, false once the module is sorted
+	visiting := make(map[string]bool, len(b.modules.list))
+	var visit func(m Module, path []string) error
+	visit = func(m Module, path []string) error {
+		path = append(path, m.GetName())
+		if v, ok := visiting[m.GetName()]; ok {
+			if v {
+				return fmt.Errorf("%w: %s", ErrModuleDependencyCycle, strings.Join(path, " -> "))
+			}
+			return nil
+		}
+		visiting[m.GetName()] = true
+		for _, d := range m.GetDependencies() {
+			dep := b.module(d)
+			if dep == nil {
+				return fmt.Errorf("%w: %s required by %s", ErrModuleNotFound, d, m.GetName())
+			}
+			if err := visit(dep, path); err != nil {
+				return err
+			}
+		}
+		visiting[m.GetName()] = false
+		sorted = append(sorted, m)
+		return nil
+	}
+	for i, m := range b.modules.list {
+		if slices.ContainsFunc(b.modules.list[:i], func(o Module) bool { return o.GetName() == m.GetName() }) {
+			return nil, fmt.Errorf("%w: %s", ErrModuleDuplicated, m.GetName())
+		}
+	}
+	for _, m := range b.modules.list {
+		if err := visit(m, nil); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
+// setupModules registers everything contained in the modules, in the order of their dependencies.
+// It is only called once.
+func (b *Bot) setupModules() error {
+	b.modules.once.Do(func() {
+		b.modules.err = b.doSetupModules()
+	})
+	return b.modules.err
+}
+
+func (b *Bot) doSetupModules() error {
+	if len(b.modules.list) == 0 {
+		return nil
+	}
+	sorted, err := b.sortModules()
+	if err != nil {
+		return err
+	}
+	if b.legacy {
+		// configs are loaded by Bot.SetupConfigs
+		if err = loadConfigs(b.moduleConfigs()); err != nil {
+			return err
+		}
+	}
+	toggleable := slices.ContainsFunc(sorted, Module.Toggleable)
+	if b.DB != nil && toggleable {
+		if err = b.DB.AutoMigrate(&DisabledModule{}); err != nil {
+			return err
+		}
+	}
+	for _, m := range sorted {
+		if len(m.GetModels()) > 0 || m.Toggleable() {
+			if b.DB == nil {
+				return fmt.Errorf("%w: %s", ErrModuleWithoutDB, m.GetName())
+			}
+			if err = b.DB.AutoMigrate(m.GetModels()...); err != nil {
+				return fmt.Errorf("migrating models of %s: %w", m.GetName(), err)
+			}
+		}
+		name := m.GetName()
+		for _, c := range m.GetCommands() {
+			if m.Toggleable() {
+				c.AddGuard(b.moduleGuard(name))
+			}
+			b.Commands = append(b.Commands, c)
+		}
+		for _, h := range m.GetHandlers() {
+			if m.Toggleable() {
+				h = b.moduleHandler(name, h)
+			}
+			b.AddHandler(h)
+		}
+		for id, h := range m.GetComponents() {
+			if m.Toggleable() {
+				h = b.moduleComponent(name, h)
+			}
+			b.HandleMessageComponent(h, id)
+		}
+		for id, h := range m.GetModals() {
+			if m.Toggleable() {
+				h = b.moduleModal(name, h)
+			}
+			b.HandleModal(h, id)
+		}
+		b.Innovations = append(b.Innovations, m.GetInnovations()...)
+		if fn := m.GetSetup(); fn != nil {
+			if err = fn(b); err != nil {
+				return fmt.Errorf("setting up %s: %w", m.GetName(), err)
+			}
+		}
+		b.logger().Debug("module set up", "module", name)
+	}
+	return nil
+}
+
+// ModuleEnabled returns true if the Module is enabled in the guild.
+// Modules are always enabled outside guilds and if they are not toggleable.
+func (b *Bot) ModuleEnabled(ctx context.Context, guildID string, name string) (bool, error) {
+	m := b.module(name)
+	if m == nil {
+		return false, fmt.Errorf("%w: %s", ErrModuleNotFound, name)
+	}
+	if guildID == "" || !m.Toggleable() {
+		return true, nil
+	}
+	disabled, err := b.disabledModules(ctx, guildID)
+	if err != nil {
+		return false, err
+	}
+	_, ok := disabled[name]
+	return !ok, nil
+}
+
+// EnableModule in the guild
+func (b *Bot) EnableModule(ctx context.Context, guildID string, name string) error {
+	if err := b.checkToggleable(name); err != nil {
+		return err
+	}
+	err := b.DB.WithContext(ctx).Where("guild_id = ? AND module = ?", guildID, name).Delete(&DisabledModule{}).Error
+	if err != nil {
+		return err
+	}
+	b.modules.mu.Lock()
+	defer b.modules.mu.Unlock()
+	if disabled, ok := b.modules.state[guildID]; ok {
+		delete(disabled, name)
+	}
+	return nil
+}
+
+// DisableModule in the guild.
+// The Module must be toggleable.
+func (b *Bot) DisableModule(ctx context.Context, guildID string, name string) error {
+	if err := b.checkToggleable(name); err != nil {
+		return err
+	}
+	err := b.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&DisabledModule{GuildID: guildID, Module: name}).Error
+	if err != nil {
+		return err
+	}
+	b.modules.mu.Lock()
+	defer b.modules.mu.Unlock()
+	if disabled, ok := b.modules.state[guildID]; ok {
+		disabled[name] = struct{}{}
+	}
+	return nil
+}
+
+func (b *Bot) checkToggleable(name string) error {
+	m := b.module(name)
+	if m == nil {
+		return fmt.Errorf("%w: %s", ErrModuleNotFound, name)
+	}
+	if !m.Toggleable() {
+		return fmt.Errorf("%w: %s", ErrModuleNotToggleable, name)
+	}
+	if b.DB == nil {
+		return fmt.Errorf("%w: %s", ErrModuleWithoutDB, name)
+	}
+	return nil
+}
+
+// disabledModules returns the modules disabled in the guild.
+// They are loaded from the database once, and kept in memory.
+func (b *Bot) disabledModules(ctx context.Context, guildID string) (map[string]struct{}, error) {
+	b.modules.mu.RLock()
+	disabled, ok := b.modules.state[guildID]
+	b.modules.mu.RUnlock()
+	if ok {
+		return disabled, nil
+	}
+	var names []string
+	err := b.DB.WithContext(ctx).Model(&DisabledModule{}).Where("guild_id = ?", guildID).Pluck("module", &names).Error
+	if err != nil {
+		return nil, err
+	}
+	disabled = make(map[string]struct{}, len(names))
+	for _, n := range names {
+		disabled[n] = struct{}{}
+	}
+	b.modules.mu.Lock()
+	defer b.modules.mu.Unlock()
+	if b.modules.state == nil {
+		b.modules.state = make(map[string]map[string]struct{})
+	}
+	b.modules.state[guildID] = disabled
+	return disabled, nil
+}
+
+// moduleEnabled returns true if the Module is enabled in the guild (errors are logged)
+func (b *Bot) moduleEnabled(ctx context.Context, guildID string, name string) bool {
+	ok, err := b.ModuleEnabled(ctx, guildID, name)
+	if err != nil {
+		// a broken database must not disable every module
+		b.Logger.Error("checking if module is enabled", "error", err, "module", name, "guild", guildID)
+		return true
+	}
+	return ok
+}
+
+// moduleGuard denies the commands of the Module if it is disabled in the guild
+func (b *Bot) moduleGuard(name string) cmd.Guard {
+	return func(ctx *cmd.GuardContext) error {
+		if !b.moduleEnabled(ctx.Context, ctx.Interaction.GuildID, name) {
+			return cmd.Deny(cmd.DeniedModule)
+		}
+		return nil
+	}
+}
+
+// moduleComponent ignores the message component if the Module is disabled in the guild
+func (b *Bot) moduleComponent(name string, h cmd.ComponentHandler) cmd.ComponentHandler {
+	return func(s bot.Session, i *event.InteractionCreate, data *interaction.MessageComponentData, resp *cmd.ResponseBuilder) {
+		if !b.moduleEnabled(resp.Context(), i.GuildID, name) {
+			b.sendModuleDisabled(resp)
+			return
+		}
+		h(s, i, data, resp)
+	}
+}
+
+// moduleModal ignores the modal if the Module is disabled in the guild
+func (b *Bot) moduleModal(name string, h cmd.ModalHandler) cmd.ModalHandler {
+	return func(s bot.Session, i *event.InteractionCreate, data *interaction.ModalSubmitData, resp *cmd.ResponseBuilder) {
+		if !b.moduleEnabled(resp.Context(), i.GuildID, name) {
+			b.sendModuleDisabled(resp)
+			return
+		}
+		h(s, i, data, resp)
+	}
+}
+
+func (b *Bot) sendModuleDisabled(resp *cmd.ResponseBuilder) {
+	locale := string(resp.Interaction().Locale)
+	err := resp.IsEphemeral().SetMessage(cmd.DenialMessage(cmd.DeniedModule, locale)).Send()
+	if err != nil {
+		b.Logger.Error("sending module disabled message", "error", err)
+	}
+}
+
+// moduleHandler wraps the event handler to ignore events of guilds where the Module is disabled.
+// Events without a guild are always handled.
+func (b *Bot) moduleHandler(name string, h any) any {
+	v := reflect.ValueOf(h)
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.NumIn() == 0 || t.NumOut() > 0 {
+		return h
+	}
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		ctx, ok := args[0].Interface().(context.Context)
+		if !ok {
+			ctx = b.context()
+		}
+		if guildID := eventField(args[len(args)-1], "GuildID"); guildID == "" || b.moduleEnabled(ctx, guildID, name) {
+			return v.Call(args)
+		}
+		return nil
+	}).Interface()
+}
@@ -0,0 +1,80 @@
+package gokord
+
+import (
+	"strings"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+)
+
+// modulesCommand returns the command enabling and disabling toggleable modules in a guild.
+// It can only be used by members with AdminPermission.
+func (b *Bot) modulesCommand() cmd.CommandBuilder {
+	newModuleOption := func() cmd.CommandOptionBuilder {
+		opt := cmd.NewOption(types.CommandOptionString, "module", "Module").IsRequired()
+		for _, m := range b.modules.list {
+			if m.Toggleable() {
+				opt.AddChoice(cmd.NewChoice(m.GetName(), m.GetName()))
+			}
+		}
+		return opt
+	}
+	perm := AdminPermission
+	return cmd.New("modules", "Manage the modules of the server").
+		SetPermission(&perm).
+		AddGuard(cmd.GuildOnly(), cmd.RequirePermissions(AdminPermission)).
+		AddSub(cmd.New("list", "List the modules of the server").
+			SetHandler(b.modulesList)).
+		AddSub(cmd.New("enable", "Enable a module in the server").
+			AddOption(newModuleOption()).
+			SetHandler(b.modulesToggle(true))).
+		AddSub(cmd.New("disable", "Disable a module in the server").
+			AddOption(newModuleOption()).
+			SetHandler(b.modulesToggle(false)))
+}
+
+func (b *Bot) modulesList(_ bot.Session, i *event.InteractionCreate, _ cmd.OptionMap, resp *cmd.ResponseBuilder) {
+	var sb strings.Builder
+	for _, m := range b.modules.list {
+		if !m.Toggleable() {
+			continue
+		}
+		state := "enabled"
+		if !b.moduleEnabled(resp.Context(), i.GuildID, m.GetName()) {
+			state = "disabled"
+		}
+		sb.WriteString("- `" + m.GetName() + "`: " + state + "\n")
+	}
+	msg := sb.String()
+	if msg == "" {
+		msg = "No module can be enabled or disabled."
+	}
+	if err := resp.IsEphemeral().SetMessage(msg).Send(); err != nil {
+		b.Logger.Error("sending modules list", "error", err)
+	}
+}
+
+func (b *Bot) modulesToggle(enable bool) cmd.CommandHandler {
+	return func(_ bot.Session, i *event.InteractionCreate, optMap cmd.OptionMap, resp *cmd.ResponseBuilder) {
+		resp.IsEphemeral()
+		name := optMap["module"].StringValue()
+		var err error
+		msg := "Module `" + name + "` "
+		if enable {
+			err = b.EnableModule(resp.Context(), i.GuildID, name)
+			msg += "enabled."
+		} else {
+			err = b.DisableModule(resp.Context(), i.GuildID, name)
+			msg += "disabled."
+		}
+		if err != nil {
+			b.Logger.Error("toggling module", "error", err, "module", name, "guild", i.GuildID)
+			msg = "Internal error, please report it"
+		}
+		if err = resp.SetMessage(msg).Send(); err != nil {
+			b.Logger.Error("sending module update", "error", err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package gokord
+
+import (
+	"slices"
+
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/user"
+)
+
+// fetchOwners fetches the owner of the application, or the members of its team, and caches them
+func (b *Bot) fetchOwners(s bot.Session) {
+	app, err := s.UserAPI().Application("@me")
+	if err != nil {
+		b.Logger.Error("fetching application owners", "error", err)
+		return
+	}
+	var owners []string
+	if app.Team != nil {
+		for _, m := range app.Team.Members {
+			if m.User != nil && m.MembershipState == user.MembershipStateAccepted {
+				owners = append(owners, m.User.ID)
+			}
+		}
+		if !slices.Contains(owners, app.Team.OwnerID) {
+			owners = append(owners, app.Team.OwnerID)
+		}
+	} else if app.Owner != nil {
+		owners = append(owners, app.Owner.ID)
+	}
+	b.ownersMu.Lock()
+	defer b.ownersMu.Unlock()
+	b.owners = owners
+	b.Logger.Debug("application owners fetched", "owners", owners)
+}
+
+// IsOwner returns true if the user is an owner of the application (or a member of its team) or is in Bot.Owners
+func (b *Bot) IsOwner(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	if slices.Contains(b.Owners, userID) {
+		return true
+	}
+	b.ownersMu.RLock()
+	defer b.ownersMu.RUnlock()
+	return slices.Contains(b.owners, userID)
+}
@@ -2,22 +2,47 @@ package gokord
 
 import (
 	"fmt"
+	"time"
 
 	cmd2 "github.com/anhgelus/gokord/cmd"
 	"github.com/nyttikord/gokord"
 	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/discord/types"
 	"github.com/nyttikord/gokord/event"
 )
 
-func pingCommand(s bot.Session, i *event.InteractionCreate, _ cmd2.OptionMap, resp *cmd2.ResponseBuilder) {
-	if err := resp.IsDeferred().Send(); err != nil { // sends the "is thinking..."
-		s.Logger().Error("respond interaction", "error", err)
-		return
-	}
+// pingCommandBuilder returns the command /ping added to every Bot
+func pingCommandBuilder() cmd2.CommandBuilder {
+	return cmd2.New("ping", "Get the ping of the bot").
+		SetHandler(pingCommand).
+		AddContext(types.InteractionContextGuild).
+		AddContext(types.InteractionContextBotDM).
+		AddContext(types.InteractionContextPrivateChannel).
+		AddIntegrationType(types.IntegrationInstallGuild).
+		AddIntegrationType(types.IntegrationInstallUser)
+}
 
-	response, err := s.InteractionAPI().Response(i.Interaction)
-	if err != nil {
-		s.Logger().Error("interaction response", "error", err)
+func pingCommand(s bot.Session, i *event.InteractionCreate, _ cmd2.OptionMap, resp *cmd2.ResponseBuilder) {
+	var sent time.Time
+	if resp.Message() != nil {
+		// prefix command: there is no interaction response, the reply is sent and then edited
+		if err := resp.SetMessage(":ping_pong: Pong !").Send(); err != nil {
+			s.Logger().Error("reply to message", "error", err)
+			return
+		}
+		sent = resp.Reply().Timestamp
+		resp.IsEdit()
+	} else {
+		if err := resp.IsDeferred().Send(); err != nil { // sends the "is thinking..."
+			s.Logger().Error("respond interaction", "error", err)
+			return
+		}
+		response, err := s.InteractionAPI().Response(i.Interaction)
+		if err != nil {
+			s.Logger().Error("interaction response", "error", err)
+			return
+		}
+		sent = response.Timestamp
 	}
 
 	var msg string
@@ -27,11 +52,11 @@ func pingCommand(s bot.Session, i *event.InteractionCreate, _ cmd2.OptionMap, re
 		s.Logger().Error("connect timestamp from ID", "error", err)
 		msg = ":ping_pong: Pong !"
 	} else {
-		msg = fmt.Sprintf(
-			":ping_pong: Pong !\nLatence du bot : `%d ms`\nLatence de l'API discord : `%d ms`",
-			response.Timestamp.Sub(timestamp).Milliseconds(),
-			s.(*gokord.Session).HeartbeatLatency().Milliseconds(),
-		)
+		msg = fmt.Sprintf(":ping_pong: Pong !\nLatence du bot : `%d ms`", sent.Sub(timestamp).Milliseconds())
+		// the latency of the gateway is unknown with HTTP interactions
+		if gs, ok := s.(*gokord.Session); ok {
+			msg += fmt.Sprintf("\nLatence de l'API discord : `%d ms`", gs.HeartbeatLatency().Milliseconds())
+		}
 	}
 
 	if err = resp.SetMessage(msg).Send(); err != nil { // modifies the "is thinking..."
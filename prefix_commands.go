@@ -0,0 +1,379 @@
+package gokord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/channel"
+	"github.com/nyttikord/gokord/discord"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/interaction"
+	"github.com/nyttikord/gokord/user"
+	"gorm.io/gorm"
+)
+
+// DefaultPrefix is the prefix used if PrefixCommands.Prefix is empty
+const DefaultPrefix = "!"
+
+var (
+	ErrPrefixCommandsDisabled = errors.New("prefix commands are disabled (Bot.PrefixCommands is nil)")
+	ErrGuildPrefixWithoutDB   = errors.New("guild prefixes require a database (Bot.DB is nil)")
+)
+
+// PrefixCommands allows using commands in messages with a prefix (like !ping), in addition to slash commands.
+// The same cmd.CommandBuilder, guards, cooldowns and handlers are used: see cmd.ParseArgs for the syntax of the
+// arguments and cmd.NewMessageResponseBuilder for the differences of the responses.
+//
+// The Bot requires the intents discord.IntentGuildMessages (or discord.IntentDirectMessages) and
+// discord.IntentMessageContent.
+type PrefixCommands struct {
+	// Prefix used in guilds without a prefix set with Bot.SetGuildPrefix, and in DMs (DefaultPrefix if empty)
+	Prefix string
+	// Mention allows using the mention of the bot as a prefix
+	Mention bool
+	// Commands usable with a prefix (every command if empty)
+	Commands []string
+}
+
+// GuildPrefix is the prefix of the prefix commands in a guild
+type GuildPrefix struct {
+	GuildID string `gorm:"primaryKey"`
+	Prefix  string
+}
+
+// prefixes of the guilds loaded
+type prefixes struct {
+	guilds map[string]string
+	mu     sync.RWMutex
+}
+
+// defaultPrefix returns PrefixCommands.Prefix, or DefaultPrefix if it is empty
+func (p *PrefixCommands) defaultPrefix() string {
+	if p.Prefix == "" {
+		return DefaultPrefix
+	}
+	return p.Prefix
+}
+
+// allows returns true if the command can be used with a prefix
+func (p *PrefixCommands) allows(name string) bool {
+	return len(p.Commands) == 0 || slices.Contains(p.Commands, name)
+}
+
+// GuildPrefix returns the prefix of the prefix commands in the guild (PrefixCommands.Prefix if it was not set)
+func (b *Bot) GuildPrefix(ctx context.Context, guildID string) (string, error) {
+	if b.PrefixCommands == nil {
+		return "", ErrPrefixCommandsDisabled
+	}
+	def := b.PrefixCommands.defaultPrefix()
+	if guildID == "" || b.DB == nil {
+		return def, nil
+	}
+	b.prefixes.mu.RLock()
+	p, ok := b.prefixes.guilds[guildID]
+	b.prefixes.mu.RUnlock()
+	if !ok {
+		var row GuildPrefix
+		err := b.DB.WithContext(ctx).Where("guild_id = ?", guildID).First(&row).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+		p = row.Prefix
+		b.cachePrefix(guildID, p)
+	}
+	if p == "" {
+		return def, nil
+	}
+	return p, nil
+}
+
+// SetGuildPrefix sets the prefix of the prefix commands in the guild.
+// If prefix is empty, PrefixCommands.Prefix is used again.
+func (b *Bot) SetGuildPrefix(ctx context.Context, guildID string, prefix string) error {
+	if b.PrefixCommands == nil {
+		return ErrPrefixCommandsDisabled
+	}
+	if b.DB == nil {
+		return ErrGuildPrefixWithoutDB
+	}
+	var err error
+	if prefix == "" {
+		err = b.DB.WithContext(ctx).Where("guild_id = ?", guildID).Delete(&GuildPrefix{}).Error
+	} else {
+		err = b.DB.WithContext(ctx).Save(&GuildPrefix{GuildID: guildID, Prefix: prefix}).Error
+	}
+	if err != nil {
+		return err
+	}
+	b.cachePrefix(guildID, prefix)
+	return nil
+}
+
+func (b *Bot) cachePrefix(guildID string, prefix string) {
+	b.prefixes.mu.Lock()
+	defer b.prefixes.mu.Unlock()
+	if b.prefixes.guilds == nil {
+		b.prefixes.guilds = make(map[string]string)
+	}
+	b.prefixes.guilds[guildID] = prefix
+}
+
+// setupPrefixCommands migrates GuildPrefix.
+// The handler of prefix commands is registered by setupCommandsHandlers.
+func (b *Bot) setupPrefixCommands() error {
+	if b.PrefixCommands == nil || b.DB == nil {
+		return nil
+	}
+	return b.DB.AutoMigrate(&GuildPrefix{})
+}
+
+// onMessagePrefix calls the command used in the message
+func (b *Bot) onMessagePrefix(ctx context.Context, s bot.Session, m *event.MessageCreate) {
+	if m.Author == nil || m.Author.Bot {
+		return
+	}
+	prefix, content, ok := b.trimPrefix(ctx, s, m.Message)
+	if !ok {
+		return
+	}
+	name, rest, _ := strings.Cut(content, " ")
+	name = strings.ToLower(name)
+	h, ok := b.cmdMap[name]
+	if !ok || !b.PrefixCommands.allows(name) {
+		return
+	}
+	c := b.command(name)
+	if c == nil || (c.GuildScoped() && !b.guildCommandEnabled(m.GuildID, name)) {
+		return
+	}
+	i := messageInteraction(s, m.Message, name)
+	resp := cmd.NewMessageResponseBuilder(s, i, m.Message).
+		SetRouter(b.Router()).
+		SetBranding(b.branding()).
+		SetServices(b.Services()).
+		SetContext(ctx)
+	if b.isBlocked(s, i, resp) {
+		return
+	}
+	if d, denied := prefixDenial(c, i); denied {
+		b.Logger.Debug("prefix command denied", "command", name, "denial", d, "user", m.Author.ID)
+		if err := resp.SetMessage(cmd.DenialMessage(d, string(i.Locale))).Send(); err != nil {
+			b.Logger.Error("sending denial message", "error", err, "command", name)
+		}
+		return
+	}
+	args, err := cmd.SplitArgs(rest)
+	var opts []*interaction.CommandInteractionDataOption
+	if err == nil {
+		opts, err = cmd.ParseArgs(c, args)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("%s\nUsage: `%s`", err, prefixUsage(prefix, c))
+		if err = resp.SetMessage(msg).Send(); err != nil {
+			b.Logger.Error("sending prefix command usage", "error", err, "command", name)
+		}
+		return
+	}
+	i.Data = &interaction.CommandInteractionData{Name: name, Options: opts}
+	b.runCommand(name, s, i, h, resp)
+}
+
+// trimPrefix returns the prefix used and the content of the message without it.
+// It returns false if the message does not start with a prefix.
+func (b *Bot) trimPrefix(ctx context.Context, s bot.Session, m *channel.Message) (string, string, bool) {
+	prefix, err := b.GuildPrefix(ctx, m.GuildID)
+	if err != nil {
+		b.Logger.Error("getting guild prefix", "error", err, "guild", m.GuildID)
+		prefix = b.PrefixCommands.defaultPrefix()
+	}
+	candidates := []string{prefix}
+	if b.PrefixCommands.Mention {
+		id := s.SessionState().User().ID
+		candidates = append(candidates, "<@"+id+">", "<@!"+id+">")
+	}
+	for _, p := range candidates {
+		if content, ok := strings.CutPrefix(m.Content, p); ok {
+			content = strings.TrimSpace(content)
+			return p, content, content != ""
+		}
+	}
+	return "", "", false
+}
+
+// command returns the cmd.CommandBuilder with the given name (nil if it does not exist)
+func (b *Bot) command(name string) cmd.CommandBuilder {
+	i := slices.IndexFunc(b.Commands, func(c cmd.CommandBuilder) bool { return c.GetName() == name })
+	if i == -1 {
+		return nil
+	}
+	return b.Commands[i]
+}
+
+// guildCommandEnabled returns true if the guild scoped command is enabled in the guild
+func (b *Bot) guildCommandEnabled(guildID string, name string) bool {
+	if b.Debug {
+		return slices.Contains(b.devGuilds(), guildID)
+	}
+	if guildID == "" || b.DB == nil {
+		return false
+	}
+	enabled, err := b.GuildCommands(guildID)
+	if err != nil {
+		b.Logger.Error("getting guild commands", "error", err, "guild", guildID)
+		return false
+	}
+	return slices.Contains(enabled, name)
+}
+
+// prefixDenial checks the contexts and the default permissions of the command like Discord does for slash commands.
+// It returns false if the command can be used.
+func prefixDenial(c cmd.CommandBuilder, i *event.InteractionCreate) (cmd.Denial, bool) {
+	ac := c.ApplicationCommand()
+	if ac.Contexts != nil {
+		if i.GuildID != "" && !slices.Contains(*ac.Contexts, types.InteractionContextGuild) {
+			return cmd.DeniedDMOnly, true
+		}
+		inDM := func(ctx types.InteractionContext) bool {
+			return ctx == types.InteractionContextBotDM || ctx == types.InteractionContextPrivateChannel
+		}
+		if i.GuildID == "" && !slices.ContainsFunc(*ac.Contexts, inDM) {
+			return cmd.DeniedGuildOnly, true
+		}
+	}
+	p := ac.DefaultMemberPermissions
+	if p == nil || i.Member == nil || i.Member.Permissions&discord.PermissionAdministrator != 0 {
+		return 0, false
+	}
+	// 0 allows administrators only
+	if *p == 0 || i.Member.Permissions&*p != *p {
+		return cmd.DeniedPermissions, true
+	}
+	return 0, false
+}
+
+// messageInteraction returns the interaction generated from the message, used by handlers of commands
+func messageInteraction(s bot.Session, m *channel.Message, name string) *event.InteractionCreate {
+	i := &interaction.Interaction{
+		ID:        m.ID,
+		Type:      types.InteractionApplicationCommand,
+		GuildID:   m.GuildID,
+		ChannelID: m.ChannelID,
+		Message:   m,
+		Data:      &interaction.CommandInteractionData{Name: name},
+	}
+	if m.GuildID == "" {
+		i.User = m.Author
+		return &event.InteractionCreate{Interaction: i}
+	}
+	member := &user.Member{}
+	if m.Member != nil {
+		cp := *m.Member
+		member = &cp
+	}
+	member.User = m.Author
+	member.GuildID = m.GuildID
+	member.Permissions = channelPermissions(s, m.ChannelID, member)
+	i.Member = member
+	if me, err := s.GuildAPI().State.Member(m.GuildID, s.SessionState().User().ID); err == nil && me != nil {
+		// the member is cached by the state
+		cp := *me
+		if cp.User == nil {
+			cp.User = s.SessionState().User()
+		}
+		i.AppPermissions = channelPermissions(s, m.ChannelID, &cp)
+	}
+	return &event.InteractionCreate{Interaction: i}
+}
+
+// guildPermissions returns the permissions of the member in the guild (overwrites of channels are not applied)
+func guildPermissions(s bot.Session, guildID string, m *user.Member) int64 {
+	g, err := s.GuildAPI().State.Guild(guildID)
+	if err != nil || g == nil || m.User == nil {
+		return 0
+	}
+	if g.OwnerID == m.User.ID {
+		return discord.PermissionAdministrator
+	}
+	var p int64
+	for _, r := range g.Roles {
+		// the ID of @everyone is the ID of the guild
+		if r.ID == guildID || slices.Contains(m.Roles, r.ID) {
+			p |= r.Permissions
+		}
+	}
+	return p
+}
+
+// channelPermissions returns the permissions of the member in the channel, with its permission overwrites applied
+func channelPermissions(s bot.Session, channelID string, m *user.Member) int64 {
+	p := guildPermissions(s, m.GuildID, m)
+	if p&discord.PermissionAdministrator != 0 || m.User == nil {
+		return p
+	}
+	ch, err := s.ChannelAPI().State.Channel(channelID)
+	if err != nil || ch == nil {
+		return p
+	}
+	// threads use the overwrites of their parent
+	if ch.IsThread() {
+		if ch, err = s.ChannelAPI().State.Channel(ch.ParentID); err != nil || ch == nil {
+			return p
+		}
+	}
+	return applyOverwrites(p, ch.PermissionOverwrites, m)
+}
+
+// applyOverwrites applies the permission overwrites of a channel to the permissions p of the member
+func applyOverwrites(p int64, overwrites []*channel.PermissionOverwrite, m *user.Member) int64 {
+	var allow, deny int64
+	for _, o := range overwrites {
+		switch {
+		case o.ID == m.GuildID:
+			// @everyone is applied first
+			p = p&^o.Deny | o.Allow
+		case slices.Contains(m.Roles, o.ID):
+			allow |= o.Allow
+			deny |= o.Deny
+		}
+	}
+	p = p&^deny | allow
+	for _, o := range overwrites {
+		if o.ID == m.User.ID {
+			p = p&^o.Deny | o.Allow
+		}
+	}
+	return p
+}
+
+// prefixUsage returns the syntax of the prefix command
+func prefixUsage(prefix string, c cmd.CommandBuilder) string {
+	formatOpts := func(opts []*interaction.CommandOption) string {
+		var sb strings.Builder
+		for _, o := range opts {
+			if o.Required {
+				sb.WriteString(" <" + o.Name + ">")
+			} else {
+				sb.WriteString(" [" + o.Name + "]")
+			}
+		}
+		return sb.String()
+	}
+	ac := c.ApplicationCommand()
+	if !c.HasSub() {
+		return prefix + ac.Name + formatOpts(ac.Options)
+	}
+	subs := make([]string, 0, len(ac.Options))
+	for _, o := range ac.Options {
+		if o.Type == types.CommandOptionSubCommand {
+			subs = append(subs, prefix+ac.Name+" "+o.Name+formatOpts(o.Options))
+		}
+	}
+	return strings.Join(subs, "` | `")
+}
@@ -0,0 +1,57 @@
+package gokord
+
+import (
+	"testing"
+
+	"github.com/nyttikord/gokord/channel"
+	"github.com/nyttikord/gokord/discord"
+	"github.com/nyttikord/gokord/user"
+)
+
+func TestApplyOverwrites(t *testing.T) {
+	const (
+		guildID = "1"
+		userID  = "2"
+		roleA   = "3"
+		roleB   = "4"
+		send    = discord.PermissionSendMessages
+		view    = discord.PermissionViewChannel
+	)
+	m := &user.Member{GuildID: guildID, User: &user.User{ID: userID}, Roles: []string{roleA, roleB}}
+	tests := []struct {
+		name       string
+		p          int64
+		overwrites []*channel.PermissionOverwrite
+		want       int64
+	}{
+		{"no overwrites", view | send, nil, view | send},
+		{"everyone denied", view | send, []*channel.PermissionOverwrite{
+			{ID: guildID, Deny: send},
+		}, view},
+		{"role allows over everyone", view, []*channel.PermissionOverwrite{
+			{ID: roleA, Allow: send},
+			{ID: guildID, Deny: send},
+		}, view | send},
+		{"role allow wins over role deny", view, []*channel.PermissionOverwrite{
+			{ID: roleA, Deny: send},
+			{ID: roleB, Allow: send},
+		}, view | send},
+		{"member overwrite last", view | send, []*channel.PermissionOverwrite{
+			{ID: userID, Deny: send},
+			{ID: roleA, Allow: send},
+		}, view},
+		{"other role ignored", view, []*channel.PermissionOverwrite{
+			{ID: "5", Allow: send},
+		}, view},
+		{"other member ignored", view | send, []*channel.PermissionOverwrite{
+			{ID: "6", Deny: send},
+		}, view | send},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyOverwrites(tt.p, tt.overwrites, m); got != tt.want {
+				t.Errorf("got %b, want %b", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,8 +10,12 @@ import (
 
 var (
 	// Credentials of redis
+	//
+	// Deprecated: use BaseConfig.GetRedisCredentials.
 	Credentials RedisCredentials
 	// Ctx background
+	//
+	// Deprecated: use the context given to the handler (cmd.ResponseBuilder.Context for commands).
 	Ctx = context.Background()
 )
 
@@ -45,9 +49,33 @@ func (rc *RedisCredentials) Connect() (*redis.Client, error) {
 	if client == nil {
 		return nil, ErrNilClient
 	}
-	err := client.Ping(Ctx).Err()
+	err := client.Ping(context.Background()).Err()
 	if err != nil {
 		return nil, err
 	}
 	return client, err
 }
+
+// useRedis returns true if the Bot uses redis
+func (b *Bot) useRedis() bool {
+	if b.legacy && !UseRedis {
+		return false
+	}
+	return b.Config != nil && b.Config.GetRedisCredentials() != nil
+}
+
+// connectRedis connects to redis with the RedisCredentials of Bot.Config.
+// The client is closed when the Bot is closed.
+func (b *Bot) connectRedis() (*redis.Client, error) {
+	if !b.useRedis() {
+		return nil, errors.Join(ErrImpossibleToConnectRedis, errors.New("redis is not used"))
+	}
+	c, err := b.Config.GetRedisCredentials().Connect()
+	if err != nil {
+		return nil, errors.Join(ErrImpossibleToConnectRedis, err)
+	}
+	b.redisClientsMu.Lock()
+	defer b.redisClientsMu.Unlock()
+	b.redisClients = append(b.redisClients, c)
+	return c, nil
+}
@@ -0,0 +1,38 @@
+package gokord
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/anhgelus/gokord/cmd"
+)
+
+var contextType = reflect.TypeFor[context.Context]()
+
+// Services returns the cmd.Services of the Bot.
+// Register services at startup (or in Module.OnSetup) with cmd.Provide, and retrieve them in handlers with cmd.Resolve
+// from the cmd.ResponseBuilder, or from the context of event handlers with cmd.ServicesFrom.
+func (b *Bot) Services() *cmd.Services {
+	b.servicesOnce.Do(func() {
+		b.services = cmd.NewServices()
+	})
+	return b.services
+}
+
+// withServices wraps the event handler to add the cmd.Services of the Bot to its context
+func (b *Bot) withServices(h any) any {
+	v := reflect.ValueOf(h)
+	t := v.Type()
+	if t.Kind() != reflect.Func || t.NumIn() == 0 || t.In(0) != contextType {
+		return h
+	}
+	services := b.Services()
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		ctx, _ := args[0].Interface().(context.Context)
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		args[0] = reflect.ValueOf(cmd.WithServices(ctx, services))
+		return v.Call(args)
+	}).Interface()
+}
@@ -0,0 +1,394 @@
+package gokord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	discordgo "github.com/nyttikord/gokord"
+	"github.com/redis/go-redis/v9"
+)
+
+// ShardsRedisKey is the prefix of the redis keys used to distribute the shards between processes
+const ShardsRedisKey = "gokord:shards"
+
+const (
+	// identifyInterval is the minimum duration between two identifications in the same bucket
+	identifyInterval = 5 * time.Second
+	// shardClaimTTL is the lifetime of a shard claimed in redis, renewed while the process is running
+	shardClaimTTL = 30 * time.Second
+)
+
+var (
+	ErrInvalidShard         = errors.New("invalid shard")
+	ErrNoShardAvailable     = errors.New("every shard is already run by another process")
+	ErrShardCountMismatch   = errors.New("shard count is different from the one used by other processes")
+	ErrShardingWithoutRedis = errors.New("distributed sharding requires redis (Config.GetRedisCredentials is nil)")
+	ErrFetchingGatewayBot   = errors.New("error while fetching the recommended number of shards")
+)
+
+// Sharding of the connection to the gateway, required by Discord when the Bot is in more than 2500 guilds.
+//
+// Each shard is a session receiving the events of its guilds.
+// One-time tasks (synchronization of commands, changelog, cleaning of development guilds) are only done by the process
+// running the shard 0 (see Bot.PrimaryShard).
+// Sharding is not used by OpenHTTP.
+type Sharding struct {
+	// Count of shards.
+	// If it is 0, the number recommended by Discord is used.
+	Count int
+	// IDs of the shards run by the process (every shard if empty)
+	IDs []int
+	// Distributed splits the shards between the processes using the same redis: each process claims the shards of IDs
+	// not run by another process.
+	// The Count is shared by these processes (the first one sets it).
+	Distributed bool
+	// PerProcess is the maximum number of shards claimed by a process if Distributed (no limit if 0)
+	PerProcess int
+}
+
+// shards run by the process, guarded by Bot.shardsMu
+type shards struct {
+	ids   []int
+	count int
+	// sessions of the shards connected to the gateway, the first one is the session of the Bot unless it was dropped
+	sessions []*discordgo.Session
+	// main is the session of the Bot, used for the API even if its shard was dropped
+	main *discordgo.Session
+	// maxConcurrency is the number of buckets identifying at the same time
+	maxConcurrency int
+	// identified contains the time of the last identification of each bucket
+	identified map[int]time.Time
+	// redis is used to claim shards if Sharding.Distributed
+	redis       *redis.Client
+	instance    string
+	renewCancel chan<- any
+}
+
+var (
+	// KEYS[1] = key, ARGV[1] = instance, ARGV[2] = ttl in ms
+	// returns 1 if the shard is still claimed by the instance
+	renewShardScript = redis.NewScript(`
+local v = redis.call("GET", KEYS[1])
+if v == ARGV[1] or not v then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0
+`)
+	// KEYS[1] = key, ARGV[1] = instance
+	releaseShardScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+)
+
+// ShardOf returns the ID of the shard receiving the events of the guild
+func ShardOf(guildID string, count int) int {
+	id, err := strconv.ParseUint(guildID, 10, 64)
+	if err != nil || count <= 1 {
+		return 0
+	}
+	return int((id >> 22) % uint64(count))
+}
+
+// ShardIDs returns the IDs of the shards run by the process (nil if the Bot is not sharded)
+func (b *Bot) ShardIDs() []int {
+	b.shardsMu.RLock()
+	defer b.shardsMu.RUnlock()
+	return slices.Clone(b.shards.ids)
+}
+
+// ShardCount returns the total number of shards (1 if the Bot is not sharded)
+func (b *Bot) ShardCount() int {
+	b.shardsMu.RLock()
+	defer b.shardsMu.RUnlock()
+	return max(b.shards.count, 1)
+}
+
+// PrimaryShard returns true if the process runs the shard 0, or if the Bot is not sharded.
+// One-time tasks must only be done by the primary shard.
+func (b *Bot) PrimaryShard() bool {
+	b.shardsMu.RLock()
+	defer b.shardsMu.RUnlock()
+	return b.shards.count == 0 || (len(b.shards.ids) > 0 && b.shards.ids[0] == 0)
+}
+
+// sessions returns the session of every shard run by the process
+func (b *Bot) sessions() []*discordgo.Session {
+	b.shardsMu.RLock()
+	defer b.shardsMu.RUnlock()
+	return slices.Clone(b.shards.sessions)
+}
+
+// setupShards chooses the shards run by the process.
+// dg is the session of the first one.
+func (b *Bot) setupShards(ctx context.Context, dg *discordgo.Session) error {
+	b.shardsMu.Lock()
+	defer b.shardsMu.Unlock()
+	b.shards = shards{sessions: []*discordgo.Session{dg}, main: dg, maxConcurrency: 1}
+	if b.Sharding == nil {
+		return nil
+	}
+	count := b.Sharding.Count
+	if count <= 0 {
+		gw, err := dg.GatewayBot()
+		if err != nil {
+			return errors.Join(ErrFetchingGatewayBot, err)
+		}
+		count = max(gw.Shards, 1)
+		b.shards.maxConcurrency = max(gw.SessionStartLimit.MaxConcurrency, 1)
+	}
+	var err error
+	if b.Sharding.Distributed {
+		err = b.claimShards(ctx, count)
+	} else {
+		b.shards.ids, err = b.Sharding.shardIDs(count)
+		b.shards.count = count
+	}
+	if err != nil {
+		return err
+	}
+	dg.Identify.Shard = &[2]int{b.shards.ids[0], b.shards.count}
+	b.logger().Info("sharding", "shards", b.shards.ids, "count", b.shards.count)
+	return nil
+}
+
+// shardIDs returns the sorted IDs of the shards that can be run by the process
+func (s *Sharding) shardIDs(count int) ([]int, error) {
+	if len(s.IDs) == 0 {
+		ids := make([]int, count)
+		for i := range ids {
+			ids[i] = i
+		}
+		return ids, nil
+	}
+	for _, id := range s.IDs {
+		if id < 0 || id >= count {
+			return nil, fmt.Errorf("%w: %d (count: %d)", ErrInvalidShard, id, count)
+		}
+	}
+	ids := slices.Clone(s.IDs)
+	slices.Sort(ids)
+	return slices.Compact(ids), nil
+}
+
+// claimShards claims the shards not run by another process in redis.
+// count is used if no other process is running.
+// Bot.shardsMu must be locked.
+func (b *Bot) claimShards(ctx context.Context, count int) error {
+	if !b.useRedis() {
+		return ErrShardingWithoutRedis
+	}
+	c, err := b.connectRedis()
+	if err != nil {
+		return err
+	}
+	countKey := ShardsRedisKey + ":count"
+	if err = c.SetNX(ctx, countKey, count, shardClaimTTL).Err(); err != nil {
+		return err
+	}
+	shared, err := c.Get(ctx, countKey).Int()
+	if err != nil {
+		return err
+	}
+	if shared != count && b.Sharding.Count > 0 {
+		return fmt.Errorf("%w: %d instead of %d", ErrShardCountMismatch, count, shared)
+	}
+	candidates, err := b.Sharding.shardIDs(shared)
+	if err != nil {
+		return err
+	}
+	b.shards.count = shared
+	b.shards.redis = c
+	b.shards.instance = instanceID()
+	for _, id := range candidates {
+		if b.Sharding.PerProcess > 0 && len(b.shards.ids) == b.Sharding.PerProcess {
+			break
+		}
+		ok, err := c.SetNX(ctx, shardKey(id), b.shards.instance, shardClaimTTL).Result()
+		if err != nil {
+			b.releaseClaims(ctx)
+			return err
+		}
+		if ok {
+			b.shards.ids = append(b.shards.ids, id)
+		}
+	}
+	if len(b.shards.ids) == 0 {
+		return ErrNoShardAvailable
+	}
+
+	ctx, instance := b.lifetime, b.shards.instance
+	b.shards.renewCancel = NewTimer(shardClaimTTL/3, func(chan<- any) {
+		if err := c.Expire(ctx, countKey, shardClaimTTL).Err(); err != nil {
+			b.Logger.Error("renewing shard count", "error", err)
+		}
+		for _, id := range b.ShardIDs() {
+			ok, err := renewShardScript.Run(ctx, c, []string{shardKey(id)}, instance, shardClaimTTL.Milliseconds()).Bool()
+			if err != nil {
+				b.Logger.Error("renewing shard", "error", err, "shard", id)
+			} else if !ok {
+				b.dropShard(ctx, id)
+			}
+		}
+	})
+	return nil
+}
+
+// dropShard closes the connection to the gateway of the shard claimed by another process, because the events would
+// be handled twice.
+//
+// If it is the shard of the session of the Bot, this session is still used for the API, but the primary duties are
+// handed over to the process now running the shard (see Bot.PrimaryShard).
+func (b *Bot) dropShard(ctx context.Context, id int) {
+	b.Logger.Error("shard claimed by another process, closing it", "shard", id)
+	b.shardsMu.Lock()
+	wasPrimary := len(b.shards.ids) > 0 && b.shards.ids[0] == 0
+	b.shards.ids = slices.DeleteFunc(b.shards.ids, func(i int) bool { return i == id })
+	var dg *discordgo.Session
+	main := false
+	b.shards.sessions = slices.DeleteFunc(b.shards.sessions, func(s *discordgo.Session) bool {
+		if s.Identify.Shard != nil && s.Identify.Shard[0] == id {
+			dg = s
+			main = s == b.shards.main
+			return true
+		}
+		return false
+	})
+	primary := b.shards.count == 0 || (len(b.shards.ids) > 0 && b.shards.ids[0] == 0)
+	b.shardsMu.Unlock()
+	if wasPrimary && !primary {
+		b.Logger.Warn("primary shard handed over to another process", "shard", id)
+	}
+	if dg == nil {
+		// not opened yet: openShards skips it
+		return
+	}
+	if main {
+		b.Logger.Warn("session of the Bot disconnected from the gateway, it is only used for the API", "shard", id)
+	}
+	b.statusTimersMu.Lock()
+	StopTimer(b.statusTimers[dg])
+	delete(b.statusTimers, dg)
+	b.statusTimersMu.Unlock()
+	if err := dg.Close(ctx); err != nil {
+		b.Logger.Error("closing shard", "error", err, "shard", id)
+		dg.ForceClose()
+	}
+}
+
+// releaseShards releases the shards claimed in redis
+func (b *Bot) releaseShards(ctx context.Context) {
+	b.shardsMu.Lock()
+	defer b.shardsMu.Unlock()
+	b.releaseClaims(ctx)
+	b.shards = shards{}
+}
+
+// releaseClaims releases the shards claimed in redis.
+// Bot.shardsMu must be locked.
+func (b *Bot) releaseClaims(ctx context.Context) {
+	c := b.shards.redis
+	if c == nil {
+		return
+	}
+	for _, id := range b.shards.ids {
+		err := releaseShardScript.Run(ctx, c, []string{shardKey(id)}, b.shards.instance).Err()
+		if err != nil {
+			b.Logger.Error("releasing shard", "error", err, "shard", id)
+		}
+	}
+}
+
+// openShards opens the sessions of the other shards run by the process.
+// If a shard cannot be opened, every session is closed.
+func (b *Bot) openShards(ctx context.Context) error {
+	ids := b.ShardIDs()
+	if len(ids) < 2 {
+		return nil
+	}
+	for _, id := range ids[1:] {
+		// the shard may have been claimed by another process in the meantime
+		if !slices.Contains(b.ShardIDs(), id) {
+			continue
+		}
+		dg := b.createSession()
+		dg.Identify.Shard = &[2]int{id, b.ShardCount()}
+		b.addHandlers(dg)
+		b.setupCommandsHandlers(dg)
+		if err := b.openSession(ctx, dg); err != nil {
+			for _, s := range b.sessions() {
+				s.ForceClose()
+			}
+			return fmt.Errorf("opening shard %d: %w", id, err)
+		}
+		b.shardsMu.Lock()
+		b.shards.sessions = append(b.shards.sessions, dg)
+		b.shardsMu.Unlock()
+	}
+	return nil
+}
+
+// waitIdentify waits until the shard can identify: Discord allows maxConcurrency identifications every 5 seconds.
+// Identifications are coordinated with redis if Sharding.Distributed.
+func (b *Bot) waitIdentify(ctx context.Context, id int) error {
+	// the lock is not held while sleeping, because the renewal of the claims needs it
+	b.shardsMu.RLock()
+	bucket := id % b.shards.maxConcurrency
+	c, instance, last := b.shards.redis, b.shards.instance, b.shards.identified[bucket]
+	b.shardsMu.RUnlock()
+	if c != nil {
+		key := ShardsRedisKey + ":identify:" + strconv.Itoa(bucket)
+		for {
+			ok, err := c.SetNX(ctx, key, instance, identifyInterval).Result()
+			if err != nil || ok {
+				return err
+			}
+			if err = sleep(ctx, time.Second); err != nil {
+				return err
+			}
+		}
+	}
+	if err := sleep(ctx, time.Until(last.Add(identifyInterval))); err != nil {
+		return err
+	}
+	b.shardsMu.Lock()
+	defer b.shardsMu.Unlock()
+	if b.shards.identified == nil {
+		b.shards.identified = make(map[int]time.Time)
+	}
+	b.shards.identified[bucket] = time.Now()
+	return nil
+}
+
+// sleep waits for the duration, or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func shardKey(id int) string {
+	return ShardsRedisKey + ":" + strconv.Itoa(id)
+}
+
+// instanceID returns an ID identifying the process in redis
+func instanceID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano())
+}
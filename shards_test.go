@@ -0,0 +1,60 @@
+package gokord
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestShardOf(t *testing.T) {
+	tests := []struct {
+		guildID string
+		count   int
+		want    int
+	}{
+		{"41771983423143937", 1, 0},
+		{"41771983423143937", 0, 0},
+		// 41771983423143937 >> 22 = 9959216934
+		{"41771983423143937", 2, 0},
+		{"41771983423143937", 10, 4},
+		{"41771983423143937", 16, 6},
+		{"4194304", 2, 1},
+		{"4194303", 2, 0},
+		{"", 4, 0},
+		{"not a snowflake", 4, 0},
+	}
+	for _, tt := range tests {
+		if got := ShardOf(tt.guildID, tt.count); got != tt.want {
+			t.Errorf("ShardOf(%q, %d) = %d, want %d", tt.guildID, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestShardingShardIDs(t *testing.T) {
+	tests := []struct {
+		name  string
+		ids   []int
+		count int
+		want  []int
+		err   error
+	}{
+		{"every shard", nil, 3, []int{0, 1, 2}, nil},
+		{"single shard", nil, 1, []int{0}, nil},
+		{"selected shards", []int{1, 3}, 4, []int{1, 3}, nil},
+		{"sorted and deduplicated", []int{2, 0, 2, 1}, 3, []int{0, 1, 2}, nil},
+		{"negative shard", []int{-1}, 3, nil, ErrInvalidShard},
+		{"shard out of range", []int{0, 3}, 3, nil, ErrInvalidShard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Sharding{IDs: tt.ids}
+			got, err := s.shardIDs(tt.count)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package gokord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultDrainTimeout is the default value of Bot.DrainTimeout
+const DefaultDrainTimeout = 10 * time.Second
+
+var ErrHandlersAbandoned = errors.New("handlers abandoned during shutdown")
+
+// ShutdownMessages are sent when an interaction is received while the Bot is shutting down, indexed by the locale of
+// the user.
+//
+// The message linked with "" is used if the locale is not present.
+var ShutdownMessages = map[string]string{
+	"":   "The bot is restarting, try again in a few moments.",
+	"fr": "Le bot redémarre, réessaye dans quelques instants.",
+}
+
+// ShutdownHook is called when the Bot is closed, after the in-flight handlers are drained
+type ShutdownHook func(ctx context.Context) error
+
+// inflight tracks handlers running.
+// A sync.WaitGroup cannot be used: abandoned handlers may still run when the Bot is opened again.
+type inflight struct {
+	mu       sync.Mutex
+	draining bool
+	next     uint64
+	running  map[uint64]inflightHandler
+	// idle is closed when the last handler ends while draining
+	idle chan struct{}
+}
+
+type inflightHandler struct {
+	name  string
+	since time.Time
+}
+
+// OnShutdown adds a ShutdownHook called when the Bot is closed.
+// Hooks are called in the reverse order of their addition, DB and redis clients opened by gokord are closed after.
+func (b *Bot) OnShutdown(hook ShutdownHook) {
+	b.shutdownHooks = append(b.shutdownHooks, hook)
+}
+
+// track a handler.
+// It returns false if the Bot is shutting down: the handler must not be called.
+// Else, the returned function must be called when the handler ends.
+func (b *Bot) track(name string) (func(), bool) {
+	in := &b.inflight
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.draining {
+		return nil, false
+	}
+	if in.running == nil {
+		in.running = make(map[uint64]inflightHandler)
+	}
+	id := in.next
+	in.next++
+	in.running[id] = inflightHandler{name: name, since: time.Now()}
+	return func() {
+		in.mu.Lock()
+		defer in.mu.Unlock()
+		delete(in.running, id)
+		if in.idle != nil && len(in.running) == 0 {
+			close(in.idle)
+			in.idle = nil
+		}
+	}, true
+}
+
+// drain stops accepting new handlers and waits for the running ones until ctx is done.
+// It returns the handlers still running.
+func (b *Bot) drain(ctx context.Context) []string {
+	in := &b.inflight
+	in.mu.Lock()
+	in.draining = true
+	n := len(in.running)
+	if n == 0 {
+		in.mu.Unlock()
+		return nil
+	}
+	idle := make(chan struct{})
+	in.idle = idle
+	in.mu.Unlock()
+	b.Logger.Info("waiting for running handlers", "count", n)
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	abandoned := make([]string, 0, len(in.running))
+	for _, h := range in.running {
+		abandoned = append(abandoned, fmt.Sprintf("%s (running for %s)", h.name, time.Since(h.since).Round(time.Millisecond)))
+	}
+	slices.Sort(abandoned)
+	return abandoned
+}
+
+// runShutdownHooks calls every ShutdownHook, then closes DB and redis clients opened by gokord
+func (b *Bot) runShutdownHooks(ctx context.Context) error {
+	var errs []error
+	for _, hook := range slices.Backward(b.shutdownHooks) {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, errors.Join(ErrHookFailed, err))
+		}
+	}
+	b.redisClientsMu.Lock()
+	for _, c := range b.redisClients {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	b.redisClients = nil
+	b.redisClientsMu.Unlock()
+	// a DB set by the user (or the deprecated global DB) is not closed
+	if b.ownedDB != nil {
+		sqlDB, err := b.ownedDB.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		b.ownedDB = nil
+	}
+	return errors.Join(errs...)
+}
@@ -8,10 +8,11 @@ import "time"
 // and fn is the functions called at each tick: it takes a chan in parameter, and you can put anything here to disable
 // the ticker
 //
-// It returns a chan to disable the timer
+// It returns a chan to disable the timer (see StopTimer)
 func NewTimer(d time.Duration, fn func(chan<- interface{})) chan<- interface{} {
 	ticker := time.NewTicker(d)
-	quit := make(chan interface{})
+	// buffered to allow fn to disable the timer
+	quit := make(chan interface{}, 1)
 	go func() {
 		// first run
 		fn(quit)
@@ -27,3 +28,16 @@ func NewTimer(d time.Duration, fn func(chan<- interface{})) chan<- interface{} {
 	}()
 	return quit
 }
+
+// StopTimer disables the timer returned by NewTimer without blocking.
+// It does nothing if quit is nil.
+func StopTimer(quit chan<- interface{}) {
+	if quit == nil {
+		return
+	}
+	select {
+	case quit <- struct{}{}:
+	default:
+		// already disabled
+	}
+}
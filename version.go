@@ -73,7 +73,7 @@ func (b *Bot) getCommandsUpdate() (*Innovation, bool) {
 		return nil, false
 	}
 	// loading bot data
-	botData := BotData{Name: b.Name}
+	botData := b.botData()
 	err := botData.Load()
 	if err != nil {
 		b.Logger.Error("loading bot data for commands update", "error", err, "name", botData.Name)
@@ -200,7 +200,7 @@ func (v *Version) String() string {
 }
 
 func (v *Version) UpdateBotVersion(bot *Bot) {
-	botData := BotData{Name: bot.Name}
+	botData := bot.botData()
 	err := botData.Load()
 	if err != nil {
 		bot.Logger.Error("loading bot data for update version", "error", err)
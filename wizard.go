@@ -0,0 +1,370 @@
+package gokord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anhgelus/gokord/cmd"
+	"github.com/nyttikord/gokord/bot"
+	"github.com/nyttikord/gokord/component"
+	"github.com/nyttikord/gokord/discord/types"
+	"github.com/nyttikord/gokord/event"
+	"github.com/nyttikord/gokord/interaction"
+)
+
+const (
+	// DefaultWizardTTL is the default duration before the expiration of a WizardState
+	DefaultWizardTTL = 15 * time.Minute
+	// WizardSelectValues is the key of the values chosen in the select menu of a WizardStep
+	WizardSelectValues = "values"
+)
+
+var (
+	ErrWizardWithoutSteps = errors.New("wizard does not have any step")
+	ErrWizardStepInvalid  = errors.New("wizard step must have a modal, a select menu or neither, not both")
+	ErrWizardNotAdded     = errors.New("wizard was not added to a bot")
+	ErrWizardWithoutStore = errors.New("wizard requires redis or a database (Bot.DB is nil)")
+)
+
+// WizardInput contains values entered by the user during a WizardStep.
+//
+// Values of a modal are indexed by the custom ID of their text input, and values of a select menu are indexed by
+// WizardSelectValues.
+type WizardInput map[string][]string
+
+// WizardState is the persisted state of a Wizard for a user
+type WizardState struct {
+	UserID  string                 `json:"user_id"`
+	GuildID string                 `json:"guild_id"`
+	Step    int                    `json:"step"`
+	Inputs  map[string]WizardInput `json:"inputs"` // Inputs validated, indexed by the name of their WizardStep
+}
+
+// WizardStep is a step of a Wizard.
+//
+// If Modal and Select are nil, the step only displays its description with a "Next" button.
+type WizardStep struct {
+	Name        string // Name of the step (must be unique in the Wizard)
+	Description string // Description displayed to the user
+	// Modal returns the text inputs of the modal opened by the step
+	Modal func(state *WizardState) []cmd.TextInputBuilder
+	// Select returns the select menu of the step, it must use the given custom ID
+	Select func(customID string, state *WizardState) cmd.SelectMenuBuilder
+	// Validate the input entered by the user (optional).
+	// The error returned is displayed to the user, who must enter a new input.
+	Validate func(state *WizardState, input WizardInput) error
+}
+
+// Wizard is a multi-step flow.
+// Its state is persisted between each step and the user can go back or cancel.
+//
+// Register it with Bot.AddWizard, and start it with Wizard.Start
+type Wizard struct {
+	Name  string // Name of the Wizard (must be unique)
+	Title string // Title displayed in messages and modals
+	Steps []*WizardStep
+	// TTL is the duration before the expiration of the WizardState (DefaultWizardTTL if 0)
+	TTL time.Duration
+	// Store used to persist WizardState.
+	// If nil, redis is used if the Bot uses it, else the database of the Bot is used
+	Store WizardStore
+	// OnComplete is called when the last step is validated.
+	// resp is in update mode and the components of the wizard are already removed: OnComplete must send it.
+	// If nil, the message of the wizard is replaced by "Completed."
+	OnComplete func(s bot.Session, i *event.InteractionCreate, state *WizardState, resp *cmd.ResponseBuilder)
+	once       sync.Once
+	storeErr   error
+	bot        *Bot
+}
+
+// Get returns the first value linked with the key
+func (in WizardInput) Get(key string) string {
+	v := in[key]
+	if len(v) == 0 {
+		return ""
+	}
+	return v[0]
+}
+
+// AddWizard registers the handlers of the Wizard
+func (b *Bot) AddWizard(w *Wizard) {
+	w.bot = b
+	for n := range w.Steps {
+		b.HandleMessageComponent(w.handleOpen(n), w.customID(n, "open"))
+		b.HandleModal(w.handleModal(n), w.customID(n, "modal"))
+		b.HandleMessageComponent(w.handleSelect(n), w.customID(n, "select"))
+		b.HandleMessageComponent(w.handleNext(n), w.customID(n, "next"))
+		b.HandleMessageComponent(w.handleBack(n), w.customID(n, "back"))
+		b.HandleMessageComponent(w.handleCancel(n), w.customID(n, "cancel"))
+	}
+}
+
+// Start the Wizard for the user who created the interaction of the cmd.ResponseBuilder.
+// The previous state of this user is erased.
+func (w *Wizard) Start(resp *cmd.ResponseBuilder) error {
+	if len(w.Steps) == 0 {
+		return ErrWizardWithoutSteps
+	}
+	i := resp.Interaction()
+	state := &WizardState{
+		UserID:  cmd.InteractionUserID(i),
+		GuildID: i.GuildID,
+		Inputs:  make(map[string]WizardInput),
+	}
+	if err := w.save(resp.Context(), state); err != nil {
+		return err
+	}
+	return w.render(resp.IsEphemeral(), state, "")
+}
+
+func (w *Wizard) store() (WizardStore, error) {
+	w.once.Do(func() {
+		if w.Store != nil {
+			return
+		}
+		if w.bot == nil {
+			w.storeErr = ErrWizardNotAdded
+			return
+		}
+		if !w.bot.useRedis() {
+			if w.bot.DB == nil {
+				w.storeErr = ErrWizardWithoutStore
+				return
+			}
+			w.Store, w.storeErr = NewDBWizardStore(w.bot.DB)
+			return
+		}
+		c, err := w.bot.connectRedis()
+		if err != nil {
+			w.storeErr = err
+			return
+		}
+		w.Store = NewRedisWizardStore(c)
+	})
+	return w.Store, w.storeErr
+}
+
+func (w *Wizard) ttl() time.Duration {
+	if w.TTL == 0 {
+		return DefaultWizardTTL
+	}
+	return w.TTL
+}
+
+func (w *Wizard) key(guildID string, userID string) string {
+	return fmt.Sprintf("gokord:wizard:%s:%s:%s", w.Name, guildID, userID)
+}
+
+func (w *Wizard) customID(step int, action string) string {
+	return fmt.Sprintf("gokord:wizard:%s:%d:%s", w.Name, step, action)
+}
+
+func (w *Wizard) load(ctx context.Context, i *event.InteractionCreate) (*WizardState, error) {
+	st, err := w.store()
+	if err != nil {
+		return nil, err
+	}
+	return st.Load(ctx, w.key(i.GuildID, cmd.InteractionUserID(i)))
+}
+
+func (w *Wizard) save(ctx context.Context, state *WizardState) error {
+	st, err := w.store()
+	if err != nil {
+		return err
+	}
+	return st.Save(ctx, w.key(state.GuildID, state.UserID), state, w.ttl())
+}
+
+func (w *Wizard) delete(ctx context.Context, i *event.InteractionCreate) error {
+	st, err := w.store()
+	if err != nil {
+		return err
+	}
+	return st.Delete(ctx, w.key(i.GuildID, cmd.InteractionUserID(i)))
+}
+
+// render the current step of the WizardState.
+// errMsg is displayed if it is not empty.
+func (w *Wizard) render(resp *cmd.ResponseBuilder, state *WizardState, errMsg string) error {
+	step := w.Steps[state.Step]
+	if step.Modal != nil && step.Select != nil {
+		return ErrWizardStepInvalid
+	}
+	var sb strings.Builder
+	if w.Title != "" {
+		sb.WriteString(fmt.Sprintf("## %s\n", w.Title))
+	}
+	sb.WriteString(fmt.Sprintf("**%s** (%d/%d)\n%s", step.Name, state.Step+1, len(w.Steps), step.Description))
+	if errMsg != "" {
+		sb.WriteString(fmt.Sprintf("\n:warning: %s", errMsg))
+	}
+	resp.SetMessage(sb.String()).SetComponents(nil)
+
+	switch {
+	case step.Modal != nil:
+		resp.AddComponent(cmd.NewActionRow().AddComponent(
+			cmd.NewButton(w.customID(state.Step, "open"), "Fill", types.ButtonStylePrimary),
+		))
+	case step.Select != nil:
+		resp.AddComponent(cmd.NewActionRow().AddComponent(step.Select(w.customID(state.Step, "select"), state)))
+	default:
+		resp.AddComponent(cmd.NewActionRow().AddComponent(
+			cmd.NewButton(w.customID(state.Step, "next"), "Next", types.ButtonStylePrimary),
+		))
+	}
+	back := cmd.NewButton(w.customID(state.Step, "back"), "Back", types.ButtonStyleSecondary)
+	if state.Step == 0 {
+		back.IsDisabled()
+	}
+	cancel := cmd.NewButton(w.customID(state.Step, "cancel"), "Cancel", types.ButtonStyleDanger)
+	return resp.AddComponent(cmd.NewActionRow().AddComponent(back).AddComponent(cancel)).Send()
+}
+
+// current returns the WizardState if the interaction was created on the current step.
+// It responds to the interaction if it is not the case.
+func (w *Wizard) current(s bot.Session, i *event.InteractionCreate, resp *cmd.ResponseBuilder, n int) *WizardState {
+	state, err := w.load(resp.Context(), i)
+	var msg string
+	if err != nil {
+		s.Logger().Error("loading wizard state", "error", err, "wizard", w.Name)
+		msg = "Internal error, please report it"
+	} else if state == nil {
+		msg = "This session has expired, please start again."
+	} else if state.Step != n {
+		msg = "This step is outdated."
+	} else {
+		return state
+	}
+	if err = resp.IsEphemeral().SetMessage(msg).Send(); err != nil {
+		s.Logger().Error("sending wizard error", "error", err, "wizard", w.Name)
+	}
+	return nil
+}
+
+// advance validates the input and goes to the next step
+func (w *Wizard) advance(s bot.Session, i *event.InteractionCreate, resp *cmd.ResponseBuilder, n int, input WizardInput) {
+	state := w.current(s, i, resp, n)
+	if state == nil {
+		return
+	}
+	step := w.Steps[n]
+	resp.IsUpdate()
+	if step.Validate != nil {
+		if err := step.Validate(state, input); err != nil {
+			if err = w.render(resp, state, err.Error()); err != nil {
+				s.Logger().Error("rendering wizard step", "error", err, "wizard", w.Name, "step", step.Name)
+			}
+			return
+		}
+	}
+	state.Inputs[step.Name] = input
+	state.Step++
+	if state.Step < len(w.Steps) {
+		errMsg := ""
+		if err := w.save(resp.Context(), state); err != nil {
+			// the user stays on the step, because the stored state was not modified
+			s.Logger().Error("saving wizard state", "error", err, "wizard", w.Name)
+			state.Step--
+			errMsg = "Internal error, please report it"
+		}
+		if err := w.render(resp, state, errMsg); err != nil {
+			s.Logger().Error("rendering wizard step", "error", err, "wizard", w.Name, "step", w.Steps[state.Step].Name)
+		}
+		return
+	}
+	if err := w.delete(resp.Context(), i); err != nil {
+		s.Logger().Error("deleting wizard state", "error", err, "wizard", w.Name)
+	}
+	resp.SetComponents([]component.Component{})
+	if w.OnComplete != nil {
+		w.OnComplete(s, i, state, resp)
+		return
+	}
+	if err := resp.SetMessage("Completed.").Send(); err != nil {
+		s.Logger().Error("sending wizard completion", "error", err, "wizard", w.Name)
+	}
+}
+
+func (w *Wizard) handleOpen(n int) cmd.ComponentHandler {
+	return func(s bot.Session, i *event.InteractionCreate, _ *interaction.MessageComponentData, resp *cmd.ResponseBuilder) {
+		state := w.current(s, i, resp, n)
+		if state == nil {
+			return
+		}
+		step := w.Steps[n]
+		title := step.Name
+		if w.Title != "" {
+			title = fmt.Sprintf("%s - %s", w.Title, step.Name)
+		}
+		resp.IsModal().SetTitle(title).SetCustomID(w.customID(n, "modal"))
+		for _, in := range step.Modal(state) {
+			resp.AddComponent(cmd.NewActionRow().AddComponent(in))
+		}
+		if err := resp.Send(); err != nil {
+			s.Logger().Error("sending wizard modal", "error", err, "wizard", w.Name, "step", step.Name)
+		}
+	}
+}
+
+func (w *Wizard) handleModal(n int) cmd.ModalHandler {
+	return func(s bot.Session, i *event.InteractionCreate, data *interaction.ModalSubmitData, resp *cmd.ResponseBuilder) {
+		input := make(WizardInput)
+		for k, v := range cmd.GenerateModalValues(data) {
+			input[k] = []string{v}
+		}
+		w.advance(s, i, resp, n, input)
+	}
+}
+
+func (w *Wizard) handleSelect(n int) cmd.ComponentHandler {
+	return func(s bot.Session, i *event.InteractionCreate, data *interaction.MessageComponentData, resp *cmd.ResponseBuilder) {
+		w.advance(s, i, resp, n, WizardInput{WizardSelectValues: data.Values})
+	}
+}
+
+func (w *Wizard) handleNext(n int) cmd.ComponentHandler {
+	return func(s bot.Session, i *event.InteractionCreate, _ *interaction.MessageComponentData, resp *cmd.ResponseBuilder) {
+		w.advance(s, i, resp, n, WizardInput{})
+	}
+}
+
+func (w *Wizard) handleBack(n int) cmd.ComponentHandler {
+	return func(s bot.Session, i *event.InteractionCreate, _ *interaction.MessageComponentData, resp *cmd.ResponseBuilder) {
+		state := w.current(s, i, resp, n)
+		if state == nil {
+			return
+		}
+		// the first step is rendered again to respond to the interaction
+		errMsg := ""
+		if n > 0 {
+			state.Step--
+			if err := w.save(resp.Context(), state); err != nil {
+				s.Logger().Error("saving wizard state", "error", err, "wizard", w.Name)
+				state.Step++
+				errMsg = "Internal error, please report it"
+			}
+		}
+		if err := w.render(resp.IsUpdate(), state, errMsg); err != nil {
+			s.Logger().Error("rendering wizard step", "error", err, "wizard", w.Name, "step", w.Steps[state.Step].Name)
+		}
+	}
+}
+
+func (w *Wizard) handleCancel(n int) cmd.ComponentHandler {
+	return func(s bot.Session, i *event.InteractionCreate, _ *interaction.MessageComponentData, resp *cmd.ResponseBuilder) {
+		if state := w.current(s, i, resp, n); state == nil {
+			return
+		}
+		if err := w.delete(resp.Context(), i); err != nil {
+			s.Logger().Error("deleting wizard state", "error", err, "wizard", w.Name)
+		}
+		err := resp.IsUpdate().SetMessage("Cancelled.").SetComponents([]component.Component{}).Send()
+		if err != nil {
+			s.Logger().Error("sending wizard cancellation", "error", err, "wizard", w.Name)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package gokord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// WizardStore persists WizardState between steps
+type WizardStore interface {
+	// Load the WizardState linked with the key.
+	// It returns nil if the state does not exist or if it is expired
+	Load(ctx context.Context, key string) (*WizardState, error)
+	// Save the WizardState linked with the key for the given duration
+	Save(ctx context.Context, key string, state *WizardState, ttl time.Duration) error
+	// Delete the WizardState linked with the key
+	Delete(ctx context.Context, key string) error
+}
+
+// redisWizardStore is a WizardStore using redis
+type redisWizardStore struct {
+	client *redis.Client
+}
+
+// dbWizardStore is a WizardStore using the database
+type dbWizardStore struct {
+	db *gorm.DB
+}
+
+// WizardData is the model used by the database WizardStore
+type WizardData struct {
+	StateKey  string `gorm:"primaryKey"`
+	Data      string
+	ExpiresAt time.Time `gorm:"index"`
+}
+
+// NewRedisWizardStore creates a WizardStore using the given redis client
+func NewRedisWizardStore(client *redis.Client) WizardStore {
+	return &redisWizardStore{client: client}
+}
+
+// NewDBWizardStore creates a WizardStore using the given database.
+// It migrates WizardData.
+func NewDBWizardStore(db *gorm.DB) (WizardStore, error) {
+	if err := db.AutoMigrate(&WizardData{}); err != nil {
+		return nil, err
+	}
+	return &dbWizardStore{db: db}, nil
+}
+
+func (r *redisWizardStore) Load(ctx context.Context, key string) (*WizardState, error) {
+	b, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var state WizardState
+	return &state, json.Unmarshal(b, &state)
+}
+
+func (r *redisWizardStore) Save(ctx context.Context, key string, state *WizardState, ttl time.Duration) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, key, b, ttl).Err()
+}
+
+func (r *redisWizardStore) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
+
+func (d *dbWizardStore) Load(ctx context.Context, key string) (*WizardState, error) {
+	var data WizardData
+	err := d.db.WithContext(ctx).Where("state_key = ?", key).First(&data).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if time.Now().After(data.ExpiresAt) {
+		return nil, d.Delete(ctx, key)
+	}
+	var state WizardState
+	return &state, json.Unmarshal([]byte(data.Data), &state)
+}
+
+func (d *dbWizardStore) Save(ctx context.Context, key string, state *WizardState, ttl time.Duration) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return d.db.WithContext(ctx).Save(&WizardData{
+		StateKey:  key,
+		Data:      string(b),
+		ExpiresAt: time.Now().Add(ttl),
+	}).Error
+}
+
+func (d *dbWizardStore) Delete(ctx context.Context, key string) error {
+	// removing expired states at the same time
+	return d.db.WithContext(ctx).
+		Where("state_key = ? OR expires_at < ?", key, time.Now()).
+		Delete(&WizardData{}).Error
+}